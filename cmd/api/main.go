@@ -1,37 +1,34 @@
 package api
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
 	"nobi-assesment/delivery/http"
 	"nobi-assesment/delivery/http/handler"
 	"nobi-assesment/delivery/http/middleware"
+	"nobi-assesment/internal/metrics"
 	"nobi-assesment/internal/repository/mysql"
 	"nobi-assesment/internal/usecase"
+	"nobi-assesment/internal/worker"
+	"nobi-assesment/pkg/config"
 	"nobi-assesment/pkg/db"
-	"os"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/notifier"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
 )
 
 func Execute() {
 	// Load environment variables
-	err := godotenv.Load()
-	if err != nil {
-		log.Println("Warning: .env file not found, using environment variables")
-	}
-
-	// Database configuration
-	dbUser := getEnv("DB_USER", "root")
-	dbPass := getEnv("DB_PASSWORD", "29/jSGGz&x0c")
-	dbHost := getEnv("DB_HOST", "localhost")
-	dbPort := getEnv("DB_PORT", "3306")
-	dbName := getEnv("DB_NAME", "nobi_investment")
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
 
 	// Initialize database connection
-	dbConn, err := db.NewMySQLConnection(dbUser, dbPass, dbHost, dbPort, dbName)
+	dbConn, err := db.NewMySQLConnectionFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		logger.Fatal("failed to connect to database", "error", err)
 	}
 	defer dbConn.Close()
 
@@ -40,22 +37,127 @@ func Execute() {
 	investmentRepo := mysql.NewMySQLInvestmentRepository(dbConn)
 	custInvestRepo := mysql.NewMySQLCustomerInvestmentRepository(dbConn)
 	transactionRepo := mysql.NewMySQLTransactionRepository(dbConn)
+	auditLogRepo := mysql.NewMySQLAuditLogRepository(dbConn)
+	riskProfileRepo := mysql.NewMySQLRiskProfileRepository(dbConn)
+	recurringPlanRepo := mysql.NewMySQLRecurringPlanRepository(dbConn)
+	walletRepo := mysql.NewMySQLWalletRepository(dbConn)
+	ledgerRepo := mysql.NewMySQLLedgerRepository(dbConn)
+	exportRepo := mysql.NewMySQLExportRepository(dbConn)
+	apiKeyRepo := mysql.NewMySQLAPIKeyRepository(dbConn)
+	tenantRepo := mysql.NewMySQLTenantRepository(dbConn)
+	transactor := mysql.NewMySQLTransactor(dbConn)
+
+	// Notifications
+	var notify notifier.Notifier = notifier.NewLogNotifier()
+	if webhookURL := config.Get("NOTIFICATION_WEBHOOK_URL", ""); webhookURL != "" {
+		notify = notifier.NewWebhookNotifier(webhookURL)
+	}
+
+	// Access policy, mapping roles to permissions
+	policy, err := middleware.LoadPolicy(config.Get("RBAC_POLICY_FILE", ""))
+	if err != nil {
+		logger.Fatal("failed to load access policy", "error", err)
+	}
 
 	// Usecase layer
-	customerUsecase := usecase.NewCustomerUsecase(customerRepo)
-	investmentUsecase := usecase.NewInvestmentUsecase(investmentRepo)
+	customerUsecase := usecase.NewCustomerUsecase(customerRepo, auditLogRepo, transactor)
+	investmentUsecase := usecase.NewInvestmentUsecase(investmentRepo, auditLogRepo, ledgerRepo, transactor)
 	transactionUsecase := usecase.NewTransactionUsecase(
 		transactionRepo,
 		customerRepo,
 		investmentRepo,
 		custInvestRepo,
-		dbConn,
+		riskProfileRepo,
+		walletRepo,
+		ledgerRepo,
+		tenantRepo,
+		auditLogRepo,
+		transactor,
+		TransactionConfig(),
+	)
+	walletUsecase := usecase.NewWalletUsecase(walletRepo, customerRepo, ledgerRepo, transactor)
+	ledgerUsecase := usecase.NewLedgerUsecase(ledgerRepo, investmentRepo, custInvestRepo, walletRepo)
+	statementUsecase := usecase.NewStatementUsecase(customerRepo, investmentRepo, transactionRepo)
+	orderBatchUsecase := usecase.NewOrderBatchUsecase(transactionUsecase, transactionRepo)
+	exportUsecase := usecase.NewExportUsecase(exportRepo)
+	tenantUsecase := usecase.NewTenantUsecase(tenantRepo)
+	auditUsecase := usecase.NewAuditUsecase(auditLogRepo, transactor)
+	apiKeyUsecase := usecase.NewAPIKeyUsecase(apiKeyRepo, transactor, policy, config.Get("API_KEY_SIGNING_KEY", ""))
+	importUsecase := usecase.NewImportUsecase(customerUsecase, investmentUsecase, transactor, config.Int("IMPORT_BATCH_SIZE", 500))
+	reconciliationUsecase := usecase.NewReconciliationUsecase(
+		investmentRepo,
+		custInvestRepo,
+		transactionRepo,
+		ledgerRepo,
+		transactor,
+		notify,
+	)
+	riskProfileUsecase := usecase.NewRiskProfileUsecase(
+		riskProfileRepo,
+		customerRepo,
+		time.Duration(config.Float("RISK_PROFILE_VALIDITY_DAYS", 365)*24)*time.Hour,
+	)
+	recurringPlanUsecase := usecase.NewRecurringPlanUsecase(
+		recurringPlanRepo,
+		customerRepo,
+		investmentRepo,
+		transactionUsecase,
+		transactor,
+		notify,
+		usecase.RecurringPlanConfig{
+			MaxAttempts: int(config.Float("RECURRING_MAX_ATTEMPTS", 3)),
+			RetryDelay:  config.Duration("RECURRING_RETRY_DELAY", time.Hour),
+			BatchSize:   100,
+			LockTTL:     5 * time.Minute,
+		},
 	)
 
 	// Handler layer
 	customerHandler := handler.NewCustomerHandler(customerUsecase)
 	investmentHandler := handler.NewInvestmentHandler(investmentUsecase)
 	transactionHandler := handler.NewTransactionHandler(transactionUsecase)
+	riskProfileHandler := handler.NewRiskProfileHandler(riskProfileUsecase)
+	recurringPlanHandler := handler.NewRecurringPlanHandler(recurringPlanUsecase)
+	walletHandler := handler.NewWalletHandler(walletUsecase)
+	ledgerHandler := handler.NewLedgerHandler(ledgerUsecase)
+	statementHandler := handler.NewStatementHandler(statementUsecase)
+	importHandler := handler.NewImportHandler(importUsecase)
+	orderBatchHandler := handler.NewOrderBatchHandler(orderBatchUsecase)
+	exportHandler := handler.NewExportHandler(exportUsecase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUsecase)
+	tenantHandler := handler.NewTenantHandler(tenantUsecase)
+	auditHandler := handler.NewAuditHandler(auditUsecase)
+
+	// Background workers, run for every tenant in turn
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if config.Bool("RECURRING_WORKER_ENABLED", true) {
+		recurringPlanWorker := worker.NewRunner(
+			"recurring-plans",
+			config.Duration("RECURRING_WORKER_INTERVAL", time.Minute),
+			worker.ForEachTenant(tenantUsecase, worker.RecurringPlanJob(recurringPlanUsecase)),
+		)
+		go recurringPlanWorker.Start(ctx)
+	}
+
+	if config.Bool("SETTLEMENT_WORKER_ENABLED", true) {
+		settlementWorker := worker.NewRunner(
+			"settlement",
+			config.Duration("SETTLEMENT_WORKER_INTERVAL", time.Minute),
+			worker.ForEachTenant(tenantUsecase, worker.SettlementJob(transactionUsecase)),
+		)
+		go settlementWorker.Start(ctx)
+	}
+
+	if config.Bool("RECONCILE_WORKER_ENABLED", false) {
+		reconciliationWorker := worker.NewRunner(
+			"reconciliation",
+			config.Duration("RECONCILE_WORKER_INTERVAL", 24*time.Hour),
+			worker.ForEachTenant(tenantUsecase, worker.ReconciliationJob(reconciliationUsecase, config.Bool("RECONCILE_AUTO_REPAIR", false))),
+		)
+		go reconciliationWorker.Start(ctx)
+	}
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
@@ -65,13 +167,111 @@ func Execute() {
 	// Setup middleware
 	middleware.SetupMiddleware(app)
 
+	// Prometheus metrics, served on their own address as they span tenants
+	if metricsAddr := config.Get("METRICS_ADDR", "localhost:9090"); metricsAddr != "" {
+		metrics.RegisterDBStats(dbConn)
+		metrics.RegisterAUM(tenantUsecase, investmentUsecase, 5*time.Second)
+		go func() {
+			slog.Info("metrics server starting", "addr", metricsAddr)
+			if err := metrics.Serve(metricsAddr); err != nil {
+				logger.Fatal("metrics server stopped", "error", err)
+			}
+		}()
+	}
+
+	// Each IP address gets one budget, checked ahead of authentication so
+	// credentials cannot be guessed at full speed, and each authenticated
+	// client separate read and write budgets
+	rateLimitEnabled := config.Bool("RATE_LIMIT_ENABLED", true)
+	rateLimitPeriod := config.Duration("RATE_LIMIT_PERIOD", time.Minute)
+	rateLimitConfig := middleware.RateLimitConfig{
+		Read:  middleware.RateLimit{Requests: config.Int("RATE_LIMIT_READ_REQUESTS", 300), Period: rateLimitPeriod},
+		Write: middleware.RateLimit{Requests: config.Int("RATE_LIMIT_WRITE_REQUESTS", 60), Period: rateLimitPeriod},
+		Store: middleware.NewMemoryRateLimitStore(),
+	}
+	ipRateLimit := middleware.RateLimit{Requests: config.Int("RATE_LIMIT_IP_REQUESTS", 600), Period: rateLimitPeriod}
+	if rateLimitEnabled {
+		if err := rateLimitConfig.Validate(); err != nil {
+			logger.Fatal("invalid rate limit", "error", err)
+		}
+		if err := ipRateLimit.Validate(); err != nil {
+			logger.Fatal("invalid rate limit", "error", fmt.Errorf("ip: %w", err))
+		}
+		app.Use("/api", middleware.IPRateLimiter(ipRateLimit, rateLimitConfig.Store))
+	}
+
+	// Partners authenticate with API keys, everyone else with bearer tokens.
+	// Bearer token authentication is enabled once a signing key is configured.
+	app.Use("/api", middleware.AuthenticateAPIKey(apiKeyUsecase))
+	jwtConfig := middleware.JWTConfig{
+		HS256Secret: config.Get("JWT_HS256_SECRET", ""),
+		JWKSFile:    config.Get("JWT_JWKS_FILE", ""),
+		Issuer:      config.Get("JWT_ISSUER", ""),
+		Audience:    config.Get("JWT_AUDIENCE", ""),
+		Leeway:      config.Duration("JWT_LEEWAY", 30*time.Second),
+	}
+	if jwtConfig.HS256Secret == "" && jwtConfig.JWKSFile == "" {
+		slog.Warn("JWT_HS256_SECRET and JWT_JWKS_FILE are not set, the API is not authenticated")
+	} else {
+		verifier, err := middleware.NewJWTVerifier(jwtConfig)
+		if err != nil {
+			logger.Fatal("failed to set up authentication", "error", err)
+		}
+		app.Use("/api", middleware.Authenticate(verifier))
+	}
+
+	// Every request is scoped to a single tenant
+	app.Use("/api", middleware.ResolveTenant(tenantUsecase))
+
+	// Partner requests to transaction routes may be signed, and must be for
+	// keys requiring it
+	app.Use("/api/transactions", middleware.VerifySignature(apiKeyUsecase, middleware.SignatureConfig{
+		Window: config.Duration("SIGNATURE_WINDOW", 5*time.Minute),
+		Nonces: middleware.NewMemoryNonceStore(),
+	}))
+
+	if rateLimitEnabled {
+		app.Use("/api", middleware.RateLimiter(rateLimitConfig))
+	}
+
 	// Setup routes
-	http.SetupRoutes(app, customerHandler, investmentHandler, transactionHandler)
+	http.SetupRoutes(
+		app,
+		policy,
+		customerHandler,
+		investmentHandler,
+		transactionHandler,
+		riskProfileHandler,
+		recurringPlanHandler,
+		walletHandler,
+		ledgerHandler,
+		statementHandler,
+		importHandler,
+		orderBatchHandler,
+		exportHandler,
+		apiKeyHandler,
+		tenantHandler,
+		auditHandler,
+	)
 
 	// Start server
-	port := getEnv("PORT", "3000")
-	log.Printf("Server started on http://localhost:%s", port)
-	log.Fatal(app.Listen(":" + port))
+	port := config.Get("PORT", "3000")
+	slog.Info("server starting", "port", port)
+	if err := app.Listen(":" + port); err != nil {
+		logger.Fatal("server stopped", "error", err)
+	}
+}
+
+// TransactionConfig reads the order rules and fees from the environment
+func TransactionConfig() usecase.TransactionConfig {
+	return usecase.TransactionConfig{
+		KYCDepositThreshold:      config.Float("KYC_DEPOSIT_THRESHOLD", 100000000),
+		AllowRiskAcknowledgement: config.Bool("RISK_ALLOW_ACKNOWLEDGEMENT", true),
+		SettlementDelay:          config.Duration("REDEMPTION_SETTLEMENT_DELAY", 24*time.Hour),
+		SettlementBatchSize:      100,
+		SubscriptionFeeRate:      config.Float("SUBSCRIPTION_FEE_RATE", 0),
+		RedemptionFeeRate:        config.Float("REDEMPTION_FEE_RATE", 0),
+	}
 }
 
 // Custom error handler for Fiber
@@ -87,11 +287,3 @@ func customErrorHandler(c *fiber.Ctx, err error) error {
 		"error": err.Error(),
 	})
 }
-
-// Helper function to get environment variables with fallback
-func getEnv(key, fallback string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return fallback
-}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository/mysql"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/config"
+	"nobi-assesment/pkg/db"
+	"nobi-assesment/pkg/logger"
+	"os"
+	"time"
+)
+
+// Execute streams a dataset into a file or stdout. With --state the export
+// is incremental: it starts from the watermark the previous run saved for the
+// dataset unless --since is given, and saves the new one on success.
+func Execute(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	dataset := flags.String("dataset", "", "dataset to export, one of transactions, holdings, nab_history")
+	format := flags.String("format", string(domain.ExportCSV), "export format, one of csv, jsonl, parquet")
+	out := flags.String("out", "-", "file the export is written to, - for stdout")
+	since := flags.String("since", "", "export rows updated at or after this RFC 3339 time or YYYY-MM-DD date")
+	state := flags.String("state", "", "JSON file keeping the watermark of each dataset between runs")
+	tenant := flags.String("tenant", domain.DefaultTenantID, "tenant whose data is exported")
+	flags.Parse(args)
+
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
+
+	dbConn, err := db.NewMySQLConnectionFromEnv()
+	if err != nil {
+		logger.Fatal("failed to connect to database", "error", err)
+	}
+	defer dbConn.Close()
+
+	exportUsecase := usecase.NewExportUsecase(mysql.NewMySQLExportRepository(dbConn))
+	exportDataset, exportFormat := domain.ExportDataset(*dataset), domain.ExportFormat(*format)
+	if err := exportUsecase.Validate(exportDataset, exportFormat); err != nil {
+		logger.Fatal("invalid export", "error", err)
+	}
+
+	from, err := usecase.ParseSince(*since)
+	if err != nil {
+		logger.Fatal("invalid since", "error", err)
+	}
+	watermarks := map[domain.ExportDataset]time.Time{}
+	if *state != "" {
+		if watermarks, err = readState(*state); err != nil {
+			logger.Fatal("failed to read state", "path", *state, "error", err)
+		}
+		if *since == "" {
+			from = watermarks[exportDataset]
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	var file *os.File
+	if *out != "-" {
+		if file, err = os.Create(*out); err != nil {
+			logger.Fatal("failed to create file", "path", *out, "error", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	result, err := exportUsecase.Export(domain.WithTenant(context.Background(), *tenant), exportDataset, exportFormat, from, w)
+	if err != nil {
+		logger.Fatal("export failed", "error", err)
+	}
+	if file != nil {
+		if err := file.Close(); err != nil {
+			logger.Fatal("failed to write file", "path", *out, "error", err)
+		}
+	}
+
+	if *state != "" {
+		watermarks[exportDataset] = result.Watermark
+		if err := writeState(*state, watermarks); err != nil {
+			logger.Fatal("failed to save state", "path", *state, "error", err)
+		}
+	}
+
+	slog.Info("exported rows", "dataset", exportDataset, "rows", result.Rows, "watermark", result.Watermark)
+}
+
+// readState reads the watermarks saved by earlier runs, none when the state
+// file does not exist yet
+func readState(path string) (map[domain.ExportDataset]time.Time, error) {
+	watermarks := map[domain.ExportDataset]time.Time{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return watermarks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return watermarks, json.Unmarshal(data, &watermarks)
+}
+
+func writeState(path string, watermarks map[domain.ExportDataset]time.Time) error {
+	data, err := json.MarshalIndent(watermarks, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository/mysql"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/config"
+	"nobi-assesment/pkg/db"
+	"nobi-assesment/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Execute imports the customers or investments of a file, or of stdin when
+// the file is "-", and prints the report as JSON. It exits with status 1
+// when any row failed.
+func Execute(args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	entity := flags.String("entity", "", "what the file holds, customers or investments")
+	format := flags.String("format", "", "csv or jsonl, detected from the file extension by default")
+	dryRun := flags.Bool("dry-run", false, "validate every row and roll back instead of creating")
+	tenant := flags.String("tenant", domain.DefaultTenantID, "tenant the rows are created in")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: import --entity customers|investments [--format csv|jsonl] [--dry-run] [--tenant TENANT] FILE")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	path := flags.Arg(0)
+	importFormat := domain.ImportFormat(*format)
+	if importFormat == "" {
+		importFormat = domain.ImportFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	}
+
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			logger.Fatal("failed to open file", "path", path, "error", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
+
+	dbConn, err := db.NewMySQLConnectionFromEnv()
+	if err != nil {
+		logger.Fatal("failed to connect to database", "error", err)
+	}
+	defer dbConn.Close()
+
+	auditLogRepo := mysql.NewMySQLAuditLogRepository(dbConn)
+	transactor := mysql.NewMySQLTransactor(dbConn)
+	importUsecase := usecase.NewImportUsecase(
+		usecase.NewCustomerUsecase(mysql.NewMySQLCustomerRepository(dbConn), auditLogRepo, transactor),
+		usecase.NewInvestmentUsecase(mysql.NewMySQLInvestmentRepository(dbConn), auditLogRepo, mysql.NewMySQLLedgerRepository(dbConn), transactor),
+		transactor,
+		config.Int("IMPORT_BATCH_SIZE", 500),
+	)
+
+	report, err := importUsecase.Import(domain.WithTenant(context.Background(), *tenant), domain.ImportEntity(*entity), importFormat, input, *dryRun)
+	if err != nil {
+		logger.Fatal("import failed", "error", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Fatal("failed to write report", "error", err)
+	}
+
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
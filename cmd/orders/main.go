@@ -0,0 +1,96 @@
+package orders
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"nobi-assesment/cmd/api"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository/mysql"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/config"
+	"nobi-assesment/pkg/db"
+	"nobi-assesment/pkg/logger"
+	"os"
+	"strings"
+)
+
+// Execute processes a batch order file and writes the results file next to
+// it. It exits with status 1 when any order failed.
+func Execute(args []string) {
+	flags := flag.NewFlagSet("orders", flag.ExitOnError)
+	out := flags.String("out", "", "results file, FILE.results.csv by default")
+	tenant := flags.String("tenant", domain.DefaultTenantID, "tenant the orders are placed in")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: orders [--out RESULTS] [--tenant TENANT] FILE")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	path := flags.Arg(0)
+	if *out == "" {
+		*out = strings.TrimSuffix(path, ".csv") + ".results.csv"
+	}
+
+	input, err := os.Open(path)
+	if err != nil {
+		logger.Fatal("failed to open file", "path", path, "error", err)
+	}
+	defer input.Close()
+
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
+
+	dbConn, err := db.NewMySQLConnectionFromEnv()
+	if err != nil {
+		logger.Fatal("failed to connect to database", "error", err)
+	}
+	defer dbConn.Close()
+
+	transactionRepo := mysql.NewMySQLTransactionRepository(dbConn)
+	transactionUsecase := usecase.NewTransactionUsecase(
+		transactionRepo,
+		mysql.NewMySQLCustomerRepository(dbConn),
+		mysql.NewMySQLInvestmentRepository(dbConn),
+		mysql.NewMySQLCustomerInvestmentRepository(dbConn),
+		mysql.NewMySQLRiskProfileRepository(dbConn),
+		mysql.NewMySQLWalletRepository(dbConn),
+		mysql.NewMySQLLedgerRepository(dbConn),
+		mysql.NewMySQLTenantRepository(dbConn),
+		mysql.NewMySQLAuditLogRepository(dbConn),
+		mysql.NewMySQLTransactor(dbConn),
+		api.TransactionConfig(),
+	)
+	orderBatchUsecase := usecase.NewOrderBatchUsecase(transactionUsecase, transactionRepo)
+
+	report, err := orderBatchUsecase.Process(domain.WithTenant(context.Background(), *tenant), input)
+	if err != nil {
+		logger.Fatal("processing failed", "error", err)
+	}
+
+	results, err := os.Create(*out)
+	if err != nil {
+		logger.Fatal("failed to create file", "path", *out, "error", err)
+	}
+	if err := usecase.WriteOrderResults(results, report); err != nil {
+		logger.Fatal("failed to write results", "path", *out, "error", err)
+	}
+	if err := results.Close(); err != nil {
+		logger.Fatal("failed to write results", "path", *out, "error", err)
+	}
+
+	slog.Info("processed orders",
+		"total", report.Total,
+		"processed", report.Processed,
+		"duplicates", report.Duplicates,
+		"failed", report.Failed,
+		"results", *out)
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,57 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository/mysql"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/config"
+	"nobi-assesment/pkg/db"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/notifier"
+	"os"
+)
+
+// Execute runs a single reconciliation and prints the report as JSON. It
+// exits with status 1 when discrepancies remain.
+func Execute(args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "overwrite stored totals and holdings with the recomputed values")
+	tenant := flags.String("tenant", domain.DefaultTenantID, "tenant to reconcile")
+	flags.Parse(args)
+
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
+
+	dbConn, err := db.NewMySQLConnectionFromEnv()
+	if err != nil {
+		logger.Fatal("failed to connect to database", "error", err)
+	}
+	defer dbConn.Close()
+
+	reconciliationUsecase := usecase.NewReconciliationUsecase(
+		mysql.NewMySQLInvestmentRepository(dbConn),
+		mysql.NewMySQLCustomerInvestmentRepository(dbConn),
+		mysql.NewMySQLTransactionRepository(dbConn),
+		mysql.NewMySQLLedgerRepository(dbConn),
+		mysql.NewMySQLTransactor(dbConn),
+		notifier.NewLogNotifier(),
+	)
+
+	report, err := reconciliationUsecase.Run(domain.WithTenant(context.Background(), *tenant), *repair)
+	if err != nil {
+		logger.Fatal("reconciliation failed", "error", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Fatal("failed to write report", "error", err)
+	}
+
+	if !report.Balanced && !report.Repaired {
+		os.Exit(1)
+	}
+}
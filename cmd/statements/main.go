@@ -0,0 +1,80 @@
+package statements
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository/mysql"
+	"nobi-assesment/internal/statement"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/config"
+	"nobi-assesment/pkg/db"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/utils"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Execute writes the statement of every customer for a period into a
+// directory, one file per customer. The period defaults to the previous
+// calendar month.
+func Execute(args []string) {
+	now := time.Now()
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+
+	flags := flag.NewFlagSet("statements", flag.ExitOnError)
+	from := flags.String("from", firstOfMonth.AddDate(0, -1, 0).Format(utils.DateLayout), "first day of the period, YYYY-MM-DD")
+	to := flags.String("to", firstOfMonth.AddDate(0, 0, -1).Format(utils.DateLayout), "last day of the period, YYYY-MM-DD")
+	format := flags.String("format", string(domain.StatementCSV), "statement format, csv or pdf")
+	dir := flags.String("dir", "statements", "directory the statements are written to")
+	tenant := flags.String("tenant", domain.DefaultTenantID, "tenant whose customers get statements")
+	flags.Parse(args)
+
+	statementFormat := domain.StatementFormat(*format)
+	if !statementFormat.IsValid() {
+		logger.Fatal("invalid format, must be one of csv, pdf", "format", *format)
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		logger.Fatal("failed to create directory", "path", *dir, "error", err)
+	}
+
+	config.Load()
+	logger.Setup(config.Get("LOG_FORMAT", "text"), config.Get("LOG_LEVEL", "info"))
+
+	dbConn, err := db.NewMySQLConnectionFromEnv()
+	if err != nil {
+		logger.Fatal("failed to connect to database", "error", err)
+	}
+	defer dbConn.Close()
+
+	statementUsecase := usecase.NewStatementUsecase(
+		mysql.NewMySQLCustomerRepository(dbConn),
+		mysql.NewMySQLInvestmentRepository(dbConn),
+		mysql.NewMySQLTransactionRepository(dbConn),
+	)
+
+	generated := 0
+	err = statementUsecase.GenerateAll(domain.WithTenant(context.Background(), *tenant), *from, *to, func(result *domain.Statement) error {
+		file, err := os.Create(filepath.Join(*dir, statement.FileName(result, statementFormat)))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err := statement.Write(file, result, statementFormat); err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		generated++
+		return nil
+	})
+	if err != nil {
+		logger.Fatal("statement generation failed", "generated", generated, "error", err)
+	}
+
+	slog.Info("generated statements", "count", generated, "from", *from, "to", *to, "dir", *dir)
+}
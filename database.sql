@@ -1,25 +1,75 @@
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(36) NOT NULL,                 -- Short slug identifying the tenant
+    name VARCHAR(255) NOT NULL,              -- Distributor the product runs for
+    host VARCHAR(255),                       -- Requests to this host belong to the tenant
+    subscription_fee_rate DECIMAL(10,6),     -- Replaces SUBSCRIPTION_FEE_RATE, NULL keeps it
+    redemption_fee_rate DECIMAL(10,6),       -- Replaces REDEMPTION_FEE_RATE, NULL keeps it
+    cut_off_time CHAR(5),                    -- HH:MM, orders placed later trade on the next day
+    enabled_investment_ids JSON NOT NULL,    -- Investments open to deposits, empty for all
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_tenant_host (host)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Records without a tenant of their own belong to the default tenant
+INSERT IGNORE INTO tenants (id, name, enabled_investment_ids) VALUES ('default', 'Default', JSON_ARRAY());
+
 CREATE TABLE IF NOT EXISTS customers (
     id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
     name VARCHAR(255) NOT NULL,         -- Full name of the customer
-    email VARCHAR(255) UNIQUE,          -- Customer email address
+    email VARCHAR(255),                 -- Customer email address
     phone VARCHAR(20),                  -- Customer phone number
+    id_number VARCHAR(32),              -- Identity document number (e.g. NIK or passport)
+    date_of_birth DATE,                 -- Customer date of birth
+    address TEXT,                       -- Customer residential address
+    kyc_status ENUM('UNVERIFIED', 'PENDING', 'VERIFIED', 'REJECTED') NOT NULL DEFAULT 'UNVERIFIED', -- KYC verification state
     is_active BOOLEAN DEFAULT TRUE,     -- Status flag (true=active, false=inactive)
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
-    PRIMARY KEY (id)
+    PRIMARY KEY (id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    UNIQUE KEY unique_customer_name (tenant_id, name),  -- Customer names are unique per tenant (case-insensitive with the table collation)
+    UNIQUE KEY unique_customer_email (tenant_id, email)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 CREATE TABLE IF NOT EXISTS investments (
     id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
     name VARCHAR(255) NOT NULL,              -- Name of the investment
     description TEXT,                        -- Detailed description of the investment
     risk_level ENUM('LOW', 'MEDIUM', 'HIGH') DEFAULT 'MEDIUM', -- Risk classification
+    category ENUM('MONEY_MARKET', 'BOND', 'EQUITY', 'MIXED'), -- Fund category
+    currency CHAR(3) NOT NULL DEFAULT 'IDR', -- ISO 4217 currency of the fund
+    manager VARCHAR(255),                    -- Investment manager running the fund
+    inception_date DATE,                     -- Date the fund was launched
+    status ENUM('OPEN', 'SUSPENDED', 'CLOSED') NOT NULL DEFAULT 'OPEN', -- Lifecycle state of the fund
+    min_initial_subscription DECIMAL(20,2) NOT NULL DEFAULT 0,    -- Minimum first deposit, 0 disables the rule
+    min_subsequent_subscription DECIMAL(20,2) NOT NULL DEFAULT 0, -- Minimum top-up deposit, 0 disables the rule
+    min_redemption DECIMAL(20,2) NOT NULL DEFAULT 0,              -- Minimum partial withdrawal, 0 disables the rule
+    min_remaining_balance DECIMAL(20,2) NOT NULL DEFAULT 0,       -- Minimum balance left after a partial withdrawal, 0 disables the rule
+    max_holding_per_customer DECIMAL(20,2) NOT NULL DEFAULT 0,    -- Maximum holding value per customer, 0 disables the rule
+    daily_subscription_limit DECIMAL(20,2) NOT NULL DEFAULT 0,    -- Maximum deposits per customer per day, 0 disables the rule
+    daily_redemption_limit DECIMAL(20,2) NOT NULL DEFAULT 0,      -- Maximum withdrawals per customer per day, 0 disables the rule
     total_units DECIMAL(20,4) DEFAULT 0,     -- Total units of investment owned
     total_balance DECIMAL(20,2) DEFAULT 0,   -- Total monetary value of investment
     current_nab DECIMAL(20,4) DEFAULT 0,     -- Current Net Asset Value per unit
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
-    PRIMARY KEY (id)
+    PRIMARY KEY (id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE RESTRICT ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS nab_history (
+    investment_id VARCHAR(36) NOT NULL,      -- Reference to investments table
+    nab_date DATE NOT NULL,                  -- Day the NAB applied to
+    nab DECIMAL(20,4) NOT NULL,              -- Last NAB orders were priced at that day
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
+    PRIMARY KEY (investment_id, nab_date),
+    FOREIGN KEY (investment_id) REFERENCES investments(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_nab_history_updated (updated_at)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 CREATE TABLE IF NOT EXISTS customer_investments (
@@ -35,27 +85,202 @@ CREATE TABLE IF NOT EXISTS customer_investments (
     PRIMARY KEY (id),
     FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
     FOREIGN KEY (investment_id) REFERENCES investments(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_customer_investment_updated (updated_at),
     UNIQUE KEY unique_customer_investment (customer_id, investment_id)  -- Ensures a customer can't have duplicate investments
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
 CREATE TABLE IF NOT EXISTS transactions (
     id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
     customer_id VARCHAR(36) NOT NULL,        -- Reference to customer who made the transaction
     investment_id VARCHAR(36) NOT NULL,      -- Reference to investment involved in transaction
-    type ENUM('DEPOSIT', 'WITHDRAW') NOT NULL, -- Transaction type (buying or selling)
-    status ENUM('PENDING', 'COMPLETED', 'FAILED', 'CANCELLED') DEFAULT 'PENDING', -- Transaction status
+    type ENUM('DEPOSIT', 'WITHDRAW', 'REVERSAL') NOT NULL, -- Transaction type (buying, selling or compensating)
+    status ENUM('PENDING', 'COMPLETED', 'FAILED', 'CANCELLED', 'REVERSED') DEFAULT 'PENDING', -- Transaction status
     amount DECIMAL(20,2) NOT NULL,           -- Monetary value of the transaction
+    fee DECIMAL(20,2) NOT NULL DEFAULT 0.00, -- Fee charged on the amount
     units DECIMAL(20,4) NOT NULL,            -- Number of investment units involved
     nab DECIMAL(20,4) NOT NULL,              -- Net Asset Value per unit at transaction time
+    nab_date DATE,                           -- Date of the NAB the transaction was priced at
+    balance_before DECIMAL(20,2) NOT NULL DEFAULT 0, -- Holding value before the transaction, at its NAB
+    balance_after DECIMAL(20,2) NOT NULL DEFAULT 0,  -- Holding value after the transaction, at its NAB
+    risk_acknowledged BOOLEAN NOT NULL DEFAULT FALSE, -- Customer accepted a risk above their risk profile
     transaction_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP, -- When the transaction occurred
+    settlement_date TIMESTAMP NULL,          -- When withdrawal proceeds are credited to the wallet
     completed_date TIMESTAMP NULL,           -- When the transaction was completed
-    notes TEXT,                              -- Additional transaction notes
+    reverses_id VARCHAR(36),                 -- Transaction a REVERSAL compensates
+    notes TEXT,                              -- Additional transaction notes, the reason of a REVERSAL
+    external_reference VARCHAR(100),         -- Partner's order reference
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
     PRIMARY KEY (id),
+    FOREIGN KEY (tenant_id) REFERENCES tenants(id) ON DELETE RESTRICT ON UPDATE CASCADE,
     FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
     FOREIGN KEY (investment_id) REFERENCES investments(id) ON DELETE RESTRICT ON UPDATE CASCADE,
     INDEX idx_transaction_date (transaction_date),
-    INDEX idx_customer_investment (customer_id, investment_id)
+    INDEX idx_customer_investment (customer_id, investment_id),
+    INDEX idx_customer_investment_type_date (customer_id, investment_id, type, transaction_date),
+    INDEX idx_transaction_settlement (status, settlement_date),
+    INDEX idx_transaction_updated (updated_at),
+    UNIQUE KEY unique_reverses_id (reverses_id),  -- A transaction is reversed at most once
+    UNIQUE KEY unique_external_reference (tenant_id, external_reference)  -- An order is processed at most once per tenant
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
+    sequence BIGINT NOT NULL,                -- Position in the tenant's hash chain, from 1
+    entity_type VARCHAR(50) NOT NULL,        -- Kind of entity that changed (e.g. CUSTOMER)
+    entity_id VARCHAR(36) NOT NULL,          -- Identifier of the entity that changed
+    action VARCHAR(50) NOT NULL,             -- What happened to the entity
+    reason TEXT,                             -- Operator supplied reason for the change
+    actor VARCHAR(255) NOT NULL,             -- Subject of the principal making the change, or system
+    request_id VARCHAR(64),                  -- Request the change was made in
+    ip_address VARCHAR(45),                  -- Address the request came from
+    before_data LONGTEXT,                    -- JSON snapshot of the entity before the change, kept as hashed
+    after_data LONGTEXT,                     -- JSON snapshot of the entity after the change, kept as hashed
+    prev_hash CHAR(64) NOT NULL,             -- Hash of the previous log in the chain, empty for the first
+    hash CHAR(64) NOT NULL,                  -- SHA-256 of the log and prev_hash
+    created_at DATETIME(6) NOT NULL,         -- Record creation time in UTC, covered by the hash
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_audit_sequence (tenant_id, sequence),
+    INDEX idx_audit_entity (tenant_id, entity_type, entity_id),
+    INDEX idx_audit_created (tenant_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Audit logs are append-only
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_update BEFORE UPDATE ON audit_logs
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+CREATE TRIGGER IF NOT EXISTS audit_logs_no_delete BEFORE DELETE ON audit_logs
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_logs is append-only';
+
+CREATE TABLE IF NOT EXISTS audit_chain_heads (
+    tenant_id VARCHAR(36) NOT NULL,          -- Reference to the tenant whose chain this is
+    sequence BIGINT NOT NULL DEFAULT 0,      -- Sequence of the tenant's last audit log
+    hash CHAR(64) NOT NULL DEFAULT '',       -- Hash of the tenant's last audit log
+    PRIMARY KEY (tenant_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS customer_risk_profiles (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    customer_id VARCHAR(36) NOT NULL,        -- Reference to the assessed customer
+    profile ENUM('CONSERVATIVE', 'MODERATE', 'AGGRESSIVE') NOT NULL, -- Resulting risk profile
+    score INT NOT NULL,                      -- Total questionnaire score
+    answers JSON NOT NULL,                   -- Answers given to the risk questionnaire
+    assessed_at TIMESTAMP NOT NULL,          -- When the questionnaire was completed
+    expires_at TIMESTAMP NOT NULL,           -- When the profile must be reassessed
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    PRIMARY KEY (id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_risk_profile_customer (customer_id, assessed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS recurring_plans (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    customer_id VARCHAR(36) NOT NULL,        -- Reference to the investing customer
+    investment_id VARCHAR(36) NOT NULL,      -- Reference to the investment deposited into
+    amount DECIMAL(20,2) NOT NULL,           -- Amount deposited on every run
+    frequency ENUM('DAILY', 'WEEKLY', 'MONTHLY') NOT NULL, -- How often the plan runs
+    start_date DATE NOT NULL,                -- First scheduled date
+    end_date DATE,                           -- Last date the plan may run, NULL runs until cancelled
+    next_run_date DATE,                      -- Next scheduled date, NULL once the plan is finished
+    status ENUM('ACTIVE', 'PAUSED', 'COMPLETED', 'CANCELLED') NOT NULL DEFAULT 'ACTIVE', -- Plan state
+    risk_acknowledged BOOLEAN NOT NULL DEFAULT FALSE, -- Customer accepted a risk above their risk profile
+    attempts INT NOT NULL DEFAULT 0,         -- Failed attempts for the current scheduled date
+    retry_at TIMESTAMP NULL,                 -- When the current scheduled date is retried
+    last_run_at TIMESTAMP NULL,              -- When a deposit was last attempted
+    last_error TEXT,                         -- Error of the last failed attempt
+    locked_until TIMESTAMP NULL,             -- Worker claim on the plan while it runs
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
+    PRIMARY KEY (id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (investment_id) REFERENCES investments(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_recurring_plan_due (status, next_run_date),
+    INDEX idx_recurring_plan_customer (customer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS recurring_plan_runs (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    plan_id VARCHAR(36) NOT NULL,            -- Reference to the recurring plan
+    scheduled_for DATE NOT NULL,             -- Scheduled date this run belongs to
+    status ENUM('SUCCEEDED', 'FAILED', 'SKIPPED') NOT NULL, -- Outcome of the run
+    attempts INT NOT NULL DEFAULT 0,         -- Deposit attempts made
+    transaction_id VARCHAR(36),              -- Deposit created by a successful run
+    error TEXT,                              -- Error of the last failed attempt
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    PRIMARY KEY (id),
+    FOREIGN KEY (plan_id) REFERENCES recurring_plans(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    UNIQUE KEY unique_plan_run (plan_id, scheduled_for)  -- One outcome per scheduled date
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS customer_wallets (
+    customer_id VARCHAR(36) NOT NULL,        -- Wallet owner, one wallet per customer
+    balance DECIMAL(20,2) NOT NULL DEFAULT 0.00, -- Cash available for subscriptions and payouts
+    pending_balance DECIMAL(20,2) NOT NULL DEFAULT 0.00, -- Redemption proceeds awaiting settlement
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
+    PRIMARY KEY (customer_id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
 
+CREATE TABLE IF NOT EXISTS wallet_movements (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    customer_id VARCHAR(36) NOT NULL,        -- Reference to the wallet owner
+    type ENUM('TOP_UP', 'PAYOUT', 'SUBSCRIPTION', 'REDEMPTION', 'REVERSAL') NOT NULL, -- Reason for the movement
+    amount DECIMAL(20,2) NOT NULL,           -- Positive for credits, negative for debits
+    balance_after DECIMAL(20,2) NOT NULL,    -- Wallet balance after the movement
+    transaction_id VARCHAR(36),              -- Subscription or redemption that moved the cash
+    reference VARCHAR(255),                  -- External reference of a top-up or payout
+    created_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6), -- Record creation timestamp
+    PRIMARY KEY (id),
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_wallet_movement_customer (customer_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
+    type ENUM('TOP_UP', 'PAYOUT', 'SUBSCRIPTION', 'REDEMPTION', 'SETTLEMENT', 'REVERSAL', 'OPENING_BALANCE') NOT NULL, -- Business event posted
+    transaction_id VARCHAR(36),              -- Transaction the entry belongs to
+    reference VARCHAR(255),                  -- External reference of a top-up or payout
+    created_at TIMESTAMP(6) DEFAULT CURRENT_TIMESTAMP(6), -- When the entry was posted
+    PRIMARY KEY (id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_journal_entry_created (tenant_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS journal_lines (
+    id BIGINT NOT NULL AUTO_INCREMENT,       -- Posting order within the journal
+    entry_id VARCHAR(36) NOT NULL,           -- Reference to the journal entry
+    account_type ENUM('CUSTOMER_CASH', 'CUSTOMER_PENDING_CASH', 'CUSTOMER_UNITS', 'FUND_CASH', 'FUND_UNITS', 'FEES', 'EXTERNAL') NOT NULL, -- Ledger account
+    customer_id VARCHAR(36),                 -- Customer owning the account, if any
+    investment_id VARCHAR(36),               -- Investment the account belongs to, if any
+    asset ENUM('CASH', 'UNITS') NOT NULL,    -- What the line moves, entries balance per asset
+    amount DECIMAL(24,4) NOT NULL,           -- Positive increases the account, negative decreases it
+    PRIMARY KEY (id),
+    FOREIGN KEY (entry_id) REFERENCES journal_entries(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+    INDEX idx_journal_line_account (account_type, customer_id, investment_id, asset),
+    INDEX idx_journal_line_customer (customer_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(36) NOT NULL DEFAULT (uuid()),  -- Primary key using UUID format
+    tenant_id VARCHAR(36) NOT NULL DEFAULT 'default', -- Reference to the owning tenant
+    name VARCHAR(255) NOT NULL,              -- Partner or integration the key was issued to
+    prefix VARCHAR(16) NOT NULL,             -- Public part of the key it is looked up by
+    key_hash CHAR(64) NOT NULL,              -- SHA-256 of the key, the key itself is never stored
+    permissions JSON NOT NULL,               -- Operations the key may perform
+    customer_ids JSON NOT NULL,              -- Customers the key may act on, empty for every customer
+    require_signature BOOLEAN NOT NULL DEFAULT FALSE, -- Transaction requests must be signed
+    rotated_from_id VARCHAR(36),             -- Key this one replaced
+    expires_at TIMESTAMP NULL,               -- When the key stops working, NULL never
+    revoked_at TIMESTAMP NULL,               -- When the key was revoked
+    last_used_at TIMESTAMP NULL,             -- When the key last authenticated a request
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,  -- Record creation timestamp
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP, -- Last update timestamp
+    PRIMARY KEY (id),
+    UNIQUE KEY unique_api_key_prefix (prefix),
+    FOREIGN KEY (rotated_from_id) REFERENCES api_keys(id) ON DELETE RESTRICT ON UPDATE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
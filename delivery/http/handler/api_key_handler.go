@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyHandler struct {
+	apiKeyUsecase usecase.APIKeyUsecase
+}
+
+func NewAPIKeyHandler(apiKeyUsecase usecase.APIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyUsecase: apiKeyUsecase,
+	}
+}
+
+// Issue responds with the new key, the only time it can be read
+func (h *APIKeyHandler) Issue(c *fiber.Ctx) error {
+	var req domain.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	issued, err := h.apiKeyUsecase.Issue(c.UserContext(), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to issue api key")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+func (h *APIKeyHandler) GetAll(c *fiber.Ctx) error {
+	keys, err := h.apiKeyUsecase.GetAll(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve api keys"})
+	}
+
+	return c.JSON(keys)
+}
+
+func (h *APIKeyHandler) GetByID(c *fiber.Ctx) error {
+	key, err := h.apiKeyUsecase.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve api key")
+	}
+
+	return c.JSON(key)
+}
+
+func (h *APIKeyHandler) Rotate(c *fiber.Ctx) error {
+	var req domain.RotateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	issued, err := h.apiKeyUsecase.Rotate(c.UserContext(), c.Params("id"), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to rotate api key")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(issued)
+}
+
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	key, err := h.apiKeyUsecase.Revoke(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to revoke api key")
+	}
+
+	return c.JSON(key)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+func NewAuditHandler(auditUsecase usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{
+		auditUsecase: auditUsecase,
+	}
+}
+
+func (h *AuditHandler) GetAll(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusBadRequest, "Invalid audit filter")
+	}
+
+	logs, err := h.auditUsecase.GetAll(c.UserContext(), filter)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve audit logs")
+	}
+
+	return c.JSON(logs)
+}
+
+func (h *AuditHandler) Verify(c *fiber.Ctx) error {
+	verification, err := h.auditUsecase.Verify(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify audit log"})
+	}
+
+	return c.JSON(verification)
+}
+
+// parseAuditFilter reads the filter from the query string, where from and
+// to are RFC 3339 times
+func parseAuditFilter(c *fiber.Ctx) (*domain.AuditLogFilter, error) {
+	filter := &domain.AuditLogFilter{
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		if value := c.Query(param.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, domain.ErrInvalidAuditFilter.WithMessage(param.name + " must be an RFC 3339 time")
+			}
+			*param.dst = t
+		}
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, domain.ErrInvalidAuditFilter.WithMessage("limit must be a number")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
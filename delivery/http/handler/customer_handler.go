@@ -24,47 +24,95 @@ func (h *CustomerHandler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
 
-	if customer.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Customer name is required"})
+	customer.ID = utils.GenerateUUID()
+
+	err := h.customerUsecase.Create(c.UserContext(), customer)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to save customer")
 	}
 
-	// Check if customer with the same name already exists
-	existingCustomers, err := h.customerUsecase.GetAll(c.Context())
+	return c.Status(fiber.StatusCreated).JSON(customer)
+}
+
+func (h *CustomerHandler) GetAll(c *fiber.Ctx) error {
+	customers, err := h.customerUsecase.GetAll(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to check customer uniqueness"})
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customers"})
 	}
 
-	for _, existingCustomer := range existingCustomers {
-		if existingCustomer.Name == customer.Name {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Customer name must be unique"})
-		}
+	return c.JSON(customers)
+}
+
+func (h *CustomerHandler) GetByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	customer, err := h.customerUsecase.GetByID(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusNotFound, "Customer not found")
 	}
 
-	customer.ID = utils.GenerateUUID()
+	return c.JSON(customer)
+}
+
+func (h *CustomerHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.UpdateCustomerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
 
-	err = h.customerUsecase.Create(c.Context(), customer)
+	customer, err := h.customerUsecase.Update(c.UserContext(), id, &req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save customer"})
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to update customer")
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(customer)
+	return c.JSON(customer)
 }
 
-func (h *CustomerHandler) GetAll(c *fiber.Ctx) error {
-	customers, err := h.customerUsecase.GetAll(c.Context())
+func (h *CustomerHandler) Deactivate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.CustomerStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	customer, err := h.customerUsecase.Deactivate(c.UserContext(), id, req.Reason)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve customers"})
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to deactivate customer")
 	}
 
-	return c.JSON(customers)
+	return c.JSON(customer)
 }
 
-func (h *CustomerHandler) GetByID(c *fiber.Ctx) error {
+func (h *CustomerHandler) Reactivate(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.CustomerStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	customer, err := h.customerUsecase.Reactivate(c.UserContext(), id, req.Reason)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to reactivate customer")
+	}
+
+	return c.JSON(customer)
+}
+
+func (h *CustomerHandler) UpdateKYCStatus(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	customer, err := h.customerUsecase.GetByID(c.Context(), id)
+	var req domain.KYCStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	customer, err := h.customerUsecase.UpdateKYCStatus(c.UserContext(), id, &req)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Customer not found"})
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to update customer kyc status")
 	}
 
 	return c.JSON(customer)
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// errorResponse writes err as JSON. Domain errors are mapped to their status
+// and code; anything else is reported with the given fallback status and
+// message so internal details are not leaked.
+func errorResponse(c *fiber.Ctx, err error, fallbackStatus int, fallbackMessage string) error {
+	e, ok := domain.AsError(err)
+	if !ok {
+		return c.Status(fallbackStatus).JSON(fiber.Map{"error": fallbackMessage})
+	}
+
+	return c.Status(statusFromKind(e.Kind)).JSON(fiber.Map{
+		"error": e.Message,
+		"code":  e.Code,
+	})
+}
+
+func statusFromKind(kind domain.ErrorKind) int {
+	switch kind {
+	case domain.KindInvalid:
+		return fiber.StatusBadRequest
+	case domain.KindNotFound:
+		return fiber.StatusNotFound
+	case domain.KindConflict:
+		return fiber.StatusConflict
+	case domain.KindUnprocessable:
+		return fiber.StatusUnprocessableEntity
+	case domain.KindUnauthenticated:
+		return fiber.StatusUnauthorized
+	case domain.KindForbidden:
+		return fiber.StatusForbidden
+	case domain.KindRateLimited:
+		return fiber.StatusTooManyRequests
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
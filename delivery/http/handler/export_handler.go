@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/export"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ExportHandler struct {
+	exportUsecase usecase.ExportUsecase
+}
+
+func NewExportHandler(exportUsecase usecase.ExportUsecase) *ExportHandler {
+	return &ExportHandler{
+		exportUsecase: exportUsecase,
+	}
+}
+
+// Export streams a dataset as it is read from the database. The request is
+// validated up front since the status is sent before the first row; a
+// failure while streaming can only truncate the body.
+func (h *ExportHandler) Export(c *fiber.Ctx) error {
+	dataset := domain.ExportDataset(c.Params("dataset"))
+	format := domain.ExportFormat(c.Query("format", string(domain.ExportCSV)))
+	if err := h.exportUsecase.Validate(dataset, format); err != nil {
+		return errorResponse(c, err, fiber.StatusBadRequest, "Invalid export")
+	}
+
+	since, err := usecase.ParseSince(c.Query("since"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusBadRequest, "Invalid since")
+	}
+
+	// The request context is recycled once the handler returns, only the
+	// tenant and request ID are carried over
+	tenantID, requestID := domain.TenantFromContext(c.UserContext()), domain.RequestInfoFromContext(c.UserContext()).ID
+	ctx := logger.WithAttrs(domain.WithTenant(context.Background(), tenantID),
+		slog.String("request_id", requestID), slog.String("tenant_id", tenantID))
+
+	c.Attachment(string(dataset) + "." + string(format))
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		result, err := h.exportUsecase.Export(ctx, dataset, format, since, w)
+		if err != nil {
+			slog.ErrorContext(ctx, "export failed", "dataset", dataset, "error", err)
+		} else {
+			slog.InfoContext(ctx, "exported rows", "dataset", dataset, "rows", result.Rows, "watermark", result.Watermark)
+		}
+		w.Flush()
+	})
+	return nil
+}
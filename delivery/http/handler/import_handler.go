@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"bytes"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	importUsecase usecase.ImportUsecase
+}
+
+func NewImportHandler(importUsecase usecase.ImportUsecase) *ImportHandler {
+	return &ImportHandler{
+		importUsecase: importUsecase,
+	}
+}
+
+// Import reads the request body as the import file
+func (h *ImportHandler) Import(c *fiber.Ctx) error {
+	entity := domain.ImportEntity(c.Params("entity"))
+	format := domain.ImportFormat(c.Query("format", string(domain.ImportCSV)))
+
+	report, err := h.importUsecase.Import(c.UserContext(), entity, format, bytes.NewReader(c.Body()), c.QueryBool("dry_run"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to import "+string(entity))
+	}
+
+	return c.JSON(report)
+}
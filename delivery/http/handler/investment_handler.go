@@ -24,34 +24,18 @@ func (h *InvestmentHandler) Create(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
 
-	if investment.Name == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Investment product name is required"})
-	}
-
-	if investment.NAB <= 0 {
-		investment.NAB = 1
-	}
-
-	if investment.TotalBalance <= 0 {
-		investment.TotalBalance = 0
-	}
-
-	if investment.TotalUnits <= 0 {
-		investment.TotalUnits = 0
-	}
-
 	investment.ID = utils.GenerateUUID()
 
-	err := h.investmentUsecase.Create(c.Context(), investment)
+	err := h.investmentUsecase.Create(c.UserContext(), investment)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save investment product"})
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to save investment product")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(investment)
 }
 
 func (h *InvestmentHandler) GetAll(c *fiber.Ctx) error {
-	investments, err := h.investmentUsecase.GetAll(c.Context())
+	investments, err := h.investmentUsecase.GetAll(c.UserContext())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve investment products"})
 	}
@@ -62,9 +46,25 @@ func (h *InvestmentHandler) GetAll(c *fiber.Ctx) error {
 func (h *InvestmentHandler) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	investment, err := h.investmentUsecase.GetByID(c.Context(), id)
+	investment, err := h.investmentUsecase.GetByID(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusNotFound, "Investment product not found")
+	}
+
+	return c.JSON(investment)
+}
+
+func (h *InvestmentHandler) Update(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.UpdateInvestmentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	investment, err := h.investmentUsecase.Update(c.UserContext(), id, &req)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Investment product not found"})
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to update investment product")
 	}
 
 	return c.JSON(investment)
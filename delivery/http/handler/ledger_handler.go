@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LedgerHandler struct {
+	ledgerUsecase usecase.LedgerUsecase
+}
+
+func NewLedgerHandler(ledgerUsecase usecase.LedgerUsecase) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerUsecase: ledgerUsecase,
+	}
+}
+
+func (h *LedgerHandler) GetEntries(c *fiber.Ctx) error {
+	entries, err := h.ledgerUsecase.GetEntries(c.UserContext(), c.Query("transaction_id"), c.Query("customer_id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve journal entries"})
+	}
+
+	return c.JSON(entries)
+}
+
+func (h *LedgerHandler) Verify(c *fiber.Ctx) error {
+	verification, err := h.ledgerUsecase.Verify(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to verify ledger"})
+	}
+
+	return c.JSON(verification)
+}
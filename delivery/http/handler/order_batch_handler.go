@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"bytes"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrderBatchHandler struct {
+	orderBatchUsecase usecase.OrderBatchUsecase
+}
+
+func NewOrderBatchHandler(orderBatchUsecase usecase.OrderBatchUsecase) *OrderBatchHandler {
+	return &OrderBatchHandler{
+		orderBatchUsecase: orderBatchUsecase,
+	}
+}
+
+// Process reads the request body as a batch order file and responds with the
+// results file, or with the JSON report when format=json
+func (h *OrderBatchHandler) Process(c *fiber.Ctx) error {
+	report, err := h.orderBatchUsecase.Process(c.UserContext(), bytes.NewReader(c.Body()))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to process orders")
+	}
+
+	if c.Query("format") == "json" {
+		return c.JSON(report)
+	}
+
+	var body bytes.Buffer
+	if err := usecase.WriteOrderResults(&body, report); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to write results"})
+	}
+
+	c.Attachment("order_results.csv")
+	c.Set(fiber.HeaderContentType, "text/csv")
+	return c.Send(body.Bytes())
+}
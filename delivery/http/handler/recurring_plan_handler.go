@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RecurringPlanHandler struct {
+	recurringPlanUsecase usecase.RecurringPlanUsecase
+}
+
+func NewRecurringPlanHandler(recurringPlanUsecase usecase.RecurringPlanUsecase) *RecurringPlanHandler {
+	return &RecurringPlanHandler{
+		recurringPlanUsecase: recurringPlanUsecase,
+	}
+}
+
+func (h *RecurringPlanHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateRecurringPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+	if err := authorizeCustomer(c, req.CustomerID); err != nil {
+		return errorResponse(c, err, fiber.StatusForbidden, "Forbidden")
+	}
+
+	plan, err := h.recurringPlanUsecase.Create(c.UserContext(), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to save recurring plan")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(plan)
+}
+
+func (h *RecurringPlanHandler) GetAll(c *fiber.Ctx) error {
+	if err := authorizeCustomer(c, c.Query("customer_id")); err != nil {
+		return errorResponse(c, err, fiber.StatusForbidden, "Forbidden")
+	}
+
+	plans, err := h.recurringPlanUsecase.GetAll(c.UserContext(), c.Query("customer_id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve recurring plans"})
+	}
+
+	return c.JSON(plans)
+}
+
+func (h *RecurringPlanHandler) GetByID(c *fiber.Ctx) error {
+	plan, err := h.recurringPlanUsecase.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+	if err := authorizeCustomer(c, plan.CustomerID); err != nil {
+		return errorResponse(c, err, fiber.StatusForbidden, "Forbidden")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) Update(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	var req domain.UpdateRecurringPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	plan, err := h.recurringPlanUsecase.Update(c.UserContext(), c.Params("id"), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to update recurring plan")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) Cancel(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	plan, err := h.recurringPlanUsecase.Cancel(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to cancel recurring plan")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) Pause(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	plan, err := h.recurringPlanUsecase.Pause(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to pause recurring plan")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) Resume(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	plan, err := h.recurringPlanUsecase.Resume(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to resume recurring plan")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) Skip(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	plan, err := h.recurringPlanUsecase.Skip(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to skip recurring plan run")
+	}
+
+	return c.JSON(plan)
+}
+
+func (h *RecurringPlanHandler) GetRuns(c *fiber.Ctx) error {
+	if err := h.authorizePlan(c, c.Params("id")); err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan")
+	}
+
+	runs, err := h.recurringPlanUsecase.GetRuns(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve recurring plan runs")
+	}
+
+	return c.JSON(runs)
+}
+
+// authorizePlan rejects customers and scoped API keys acting on the plan of
+// a customer out of their reach
+func (h *RecurringPlanHandler) authorizePlan(c *fiber.Ctx, id string) error {
+	principal, ok := domain.PrincipalFromContext(c.UserContext())
+	if !ok || !principal.HasCustomerScope() {
+		return nil
+	}
+
+	plan, err := h.recurringPlanUsecase.GetByID(c.UserContext(), id)
+	if err != nil {
+		return err
+	}
+	return authorizeCustomer(c, plan.CustomerID)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RiskProfileHandler struct {
+	riskProfileUsecase usecase.RiskProfileUsecase
+}
+
+func NewRiskProfileHandler(riskProfileUsecase usecase.RiskProfileUsecase) *RiskProfileHandler {
+	return &RiskProfileHandler{
+		riskProfileUsecase: riskProfileUsecase,
+	}
+}
+
+func (h *RiskProfileHandler) GetQuestionnaire(c *fiber.Ctx) error {
+	return c.JSON(h.riskProfileUsecase.GetQuestionnaire(c.UserContext()))
+}
+
+func (h *RiskProfileHandler) Submit(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	var req domain.RiskProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	profile, err := h.riskProfileUsecase.Submit(c.UserContext(), customerID, &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to save risk profile")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(profile)
+}
+
+func (h *RiskProfileHandler) GetCurrent(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	profile, err := h.riskProfileUsecase.GetCurrent(c.UserContext(), customerID)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve risk profile")
+	}
+
+	return c.JSON(profile)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"bytes"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/statement"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatementHandler struct {
+	statementUsecase usecase.StatementUsecase
+}
+
+func NewStatementHandler(statementUsecase usecase.StatementUsecase) *StatementHandler {
+	return &StatementHandler{
+		statementUsecase: statementUsecase,
+	}
+}
+
+func (h *StatementHandler) Generate(c *fiber.Ctx) error {
+	format := domain.StatementFormat(c.Query("format", string(domain.StatementCSV)))
+	if !format.IsValid() {
+		return errorResponse(c, domain.ErrInvalidStatementFormat, fiber.StatusBadRequest, "Invalid format")
+	}
+
+	result, err := h.statementUsecase.Generate(c.UserContext(), c.Params("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to generate statement")
+	}
+
+	var body bytes.Buffer
+	if err := statement.Write(&body, result, format); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to render statement"})
+	}
+
+	c.Attachment(statement.FileName(result, format))
+	c.Set(fiber.HeaderContentType, statement.ContentType(format))
+	return c.Send(body.Bytes())
+}
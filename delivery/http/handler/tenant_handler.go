@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TenantHandler struct {
+	tenantUsecase usecase.TenantUsecase
+}
+
+func NewTenantHandler(tenantUsecase usecase.TenantUsecase) *TenantHandler {
+	return &TenantHandler{
+		tenantUsecase: tenantUsecase,
+	}
+}
+
+func (h *TenantHandler) Create(c *fiber.Ctx) error {
+	var req domain.CreateTenantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	tenant, err := h.tenantUsecase.Create(c.UserContext(), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to create tenant")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tenant)
+}
+
+func (h *TenantHandler) GetAll(c *fiber.Ctx) error {
+	tenants, err := h.tenantUsecase.GetAll(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to retrieve tenants"})
+	}
+
+	return c.JSON(tenants)
+}
+
+func (h *TenantHandler) GetByID(c *fiber.Ctx) error {
+	tenant, err := h.tenantUsecase.GetByID(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve tenant")
+	}
+
+	return c.JSON(tenant)
+}
+
+func (h *TenantHandler) Update(c *fiber.Ctx) error {
+	var req domain.UpdateTenantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	tenant, err := h.tenantUsecase.Update(c.UserContext(), c.Params("id"), &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to update tenant")
+	}
+
+	return c.JSON(tenant)
+}
@@ -22,10 +22,13 @@ func (h *TransactionHandler) Deposit(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
+	if err := authorizeCustomer(c, req.CustomerID); err != nil {
+		return transactionErrorResponse(c, err)
+	}
 
-	resp, err := h.transactionUsecase.Deposit(c.Context(), &req)
+	resp, err := h.transactionUsecase.Deposit(c.UserContext(), &req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return transactionErrorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
@@ -36,19 +39,48 @@ func (h *TransactionHandler) Withdraw(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
 	}
+	if err := authorizeCustomer(c, req.CustomerID); err != nil {
+		return transactionErrorResponse(c, err)
+	}
 
-	resp, err := h.transactionUsecase.Withdraw(c.Context(), &req)
+	resp, err := h.transactionUsecase.Withdraw(c.UserContext(), &req)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		return transactionErrorResponse(c, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
 
+func (h *TransactionHandler) Reverse(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var req domain.ReverseTransactionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	if principal, ok := domain.PrincipalFromContext(c.UserContext()); ok && principal.HasCustomerScope() {
+		transaction, err := h.transactionUsecase.GetTransaction(c.UserContext(), id)
+		if err == nil {
+			err = authorizeCustomer(c, transaction.CustomerID)
+		}
+		if err != nil {
+			return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to reverse transaction")
+		}
+	}
+
+	reversal, err := h.transactionUsecase.Reverse(c.UserContext(), id, &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to reverse transaction")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reversal)
+}
+
 func (h *TransactionHandler) GetCustomerTransactions(c *fiber.Ctx) error {
 	customerID := c.Params("id")
 
-	transactions, err := h.transactionUsecase.GetCustomerTransactions(c.Context(), customerID)
+	transactions, err := h.transactionUsecase.GetCustomerTransactions(c.UserContext(), customerID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -56,14 +88,37 @@ func (h *TransactionHandler) GetCustomerTransactions(c *fiber.Ctx) error {
 	return c.JSON(transactions)
 }
 
+func (h *TransactionHandler) GetTransaction(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	transaction, err := h.transactionUsecase.GetTransaction(c.UserContext(), id)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to get transaction")
+	}
+	if err := authorizeCustomer(c, transaction.CustomerID); err != nil {
+		return errorResponse(c, err, fiber.StatusForbidden, "Forbidden")
+	}
+
+	return c.JSON(transaction)
+}
+
 func (h *TransactionHandler) GetCustomerPortfolio(c *fiber.Ctx) error {
 	customerID := c.Params("customer_id")
 	investmentID := c.Params("investment_id")
 
-	portfolio, err := h.transactionUsecase.GetCustomerPortfolio(c.Context(), customerID, investmentID)
+	portfolio, err := h.transactionUsecase.GetCustomerPortfolio(c.UserContext(), customerID, investmentID)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(portfolio)
 }
+
+// transactionErrorResponse reports domain errors with their own status and
+// everything else as a bad request carrying the error message.
+func transactionErrorResponse(c *fiber.Ctx, err error) error {
+	if _, ok := domain.AsError(err); ok {
+		return errorResponse(c, err, fiber.StatusBadRequest, err.Error())
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WalletHandler struct {
+	walletUsecase usecase.WalletUsecase
+}
+
+func NewWalletHandler(walletUsecase usecase.WalletUsecase) *WalletHandler {
+	return &WalletHandler{
+		walletUsecase: walletUsecase,
+	}
+}
+
+func (h *WalletHandler) Get(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	wallet, err := h.walletUsecase.Get(c.UserContext(), customerID)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve wallet")
+	}
+
+	return c.JSON(wallet)
+}
+
+func (h *WalletHandler) TopUp(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	var req domain.WalletFundsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	movement, err := h.walletUsecase.TopUp(c.UserContext(), customerID, &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to top up wallet")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(movement)
+}
+
+func (h *WalletHandler) Payout(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	var req domain.WalletFundsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Cannot parse JSON"})
+	}
+
+	movement, err := h.walletUsecase.Payout(c.UserContext(), customerID, &req)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to pay out from wallet")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(movement)
+}
+
+func (h *WalletHandler) GetMovements(c *fiber.Ctx) error {
+	customerID := c.Params("id")
+
+	movements, err := h.walletUsecase.GetMovements(c.UserContext(), customerID)
+	if err != nil {
+		return errorResponse(c, err, fiber.StatusInternalServerError, "Failed to retrieve wallet movements")
+	}
+
+	return c.JSON(movements)
+}
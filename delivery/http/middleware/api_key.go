@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey carries the API key of partners calling server to server
+const HeaderAPIKey = "X-API-Key"
+
+// AuthenticateAPIKey authenticates requests sending an API key as the
+// partner it was issued to, limited to the key's permissions and customers.
+// Requests without one are left to bearer token authentication.
+func AuthenticateAPIKey(apiKeyUsecase usecase.APIKeyUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		secret := c.Get(HeaderAPIKey)
+		if secret == "" {
+			return c.Next()
+		}
+
+		key, err := apiKeyUsecase.Authenticate(c.UserContext(), secret)
+		if err != nil {
+			if e, ok := domain.AsError(err); ok {
+				return reject(c, fiber.StatusUnauthorized, e)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to authenticate api key"})
+		}
+
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), key.Principal()))
+		return c.Next()
+	}
+}
+
+// RequireUnscoped keeps principals limited to some customers out of routes
+// spanning every customer
+func RequireUnscoped() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := domain.PrincipalFromContext(c.UserContext())
+		if ok && principal.HasCustomerScope() {
+			return reject(c, fiber.StatusForbidden, domain.ErrForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// RejectAPIKeys keeps partners off administrative routes, such as managing
+// API keys, which would let a key issue itself broader ones
+func RejectAPIKeys() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := domain.PrincipalFromContext(c.UserContext())
+		if ok && principal.APIKeyID != "" {
+			return reject(c, fiber.StatusForbidden, domain.ErrForbidden.WithMessage("api keys cannot use this route"))
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig sets the keys and claims tokens are validated against. At least
+// one of HS256Secret and JWKSFile must be set.
+type JWTConfig struct {
+	HS256Secret string
+	JWKSFile    string // JSON Web Key Set holding the RS256 public keys
+	Issuer      string // Required iss claim, when set
+	Audience    string // Required aud claim, when set
+	Leeway      time.Duration
+}
+
+// JWTVerifier validates bearer tokens and maps their claims to a principal
+type JWTVerifier struct {
+	secret  []byte
+	rsaKeys map[string]*rsa.PublicKey
+	parser  *jwt.Parser
+}
+
+// tokenClaims are the claims a principal is built from. Tokens carrying a
+// customer_id act on behalf of that customer only, with the customer role,
+// and tokens carrying a tenant_id only within that tenant.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	TenantID   string   `json:"tenant_id"`
+	CustomerID string   `json:"customer_id"`
+	Roles      []string `json:"roles"`
+}
+
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	verifier := &JWTVerifier{}
+	var methods []string
+	if cfg.HS256Secret != "" {
+		verifier.secret = []byte(cfg.HS256Secret)
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.rsaKeys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no HS256 secret or JWKS file configured")
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	return verifier, nil
+}
+
+// Verify validates a token and returns the principal it identifies
+func (v *JWTVerifier) Verify(token string) (*domain.Principal, error) {
+	claims := &tokenClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	// Customers hold the customer role and nothing else, and the role is
+	// meaningless without the customer it is scoped to
+	roles := claims.Roles
+	if claims.CustomerID != "" {
+		roles = []string{domain.RoleCustomer}
+	} else if slices.Contains(roles, domain.RoleCustomer) {
+		return nil, errors.New("customer token has no customer_id")
+	}
+
+	return &domain.Principal{
+		Subject:    claims.Subject,
+		TenantID:   claims.TenantID,
+		CustomerID: claims.CustomerID,
+		Roles:      roles,
+	}, nil
+}
+
+// key picks the key a token is verified with, the RS256 key by the token's
+// kid header or the only one when the set holds a single key
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.rsaKeys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+	return nil, errors.New("unknown signing key")
+}
+
+// Authenticate rejects requests without a valid bearer token and stores the
+// principal of the token in the request context. Requests already
+// authenticated with an API key are let through.
+func Authenticate(verifier *JWTVerifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if _, ok := domain.PrincipalFromContext(c.UserContext()); ok {
+			return c.Next()
+		}
+
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			return reject(c, fiber.StatusUnauthorized, domain.ErrUnauthenticated)
+		}
+
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			return reject(c, fiber.StatusUnauthorized, domain.ErrUnauthenticated.WithMessage("invalid token: "+err.Error()))
+		}
+
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// RequireOwnCustomer rejects customers and scoped API keys acting on a
+// customer ID out of their reach, read from the route parameter
+func RequireOwnCustomer(param string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := domain.PrincipalFromContext(c.UserContext())
+		if ok && !principal.CanAccessCustomer(c.Params(param)) {
+			return reject(c, fiber.StatusForbidden, domain.ErrForbidden)
+		}
+		return c.Next()
+	}
+}
+
+// reject writes a domain error in the same shape as the handlers do
+func reject(c *fiber.Ctx, status int, e *domain.Error) error {
+	return c.Status(status).JSON(fiber.Map{
+		"error": e.Message,
+		"code":  e.Code,
+	})
+}
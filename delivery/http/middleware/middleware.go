@@ -1,18 +1,24 @@
 package middleware
 
 import (
+	"nobi-assesment/pkg/signature"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 )
 
 // SetupMiddleware sets up all middleware for the application
 func SetupMiddleware(app *fiber.App) {
-	// Logger middleware
-	app.Use(logger.New(logger.Config{
-		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
-	}))
+	// Request ID and client address, recorded with every change and log
+	app.Use(RequestInfo())
+
+	// Request log, once per request
+	app.Use(LogRequests())
+
+	// Request latency histograms
+	app.Use(RecordMetrics())
 
 	// Recover middleware
 	app.Use(recover.New())
@@ -20,7 +26,25 @@ func SetupMiddleware(app *fiber.App) {
 	// CORS middleware
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowMethods: "GET,POST,PUT,DELETE",
-		AllowHeaders: "Origin, Content-Type, Accept",
+		AllowMethods: "GET,POST,PUT,PATCH,DELETE",
+		AllowHeaders: strings.Join([]string{
+			fiber.HeaderOrigin,
+			fiber.HeaderContentType,
+			fiber.HeaderAccept,
+			fiber.HeaderAuthorization,
+			HeaderAPIKey,
+			signature.HeaderSignature,
+			signature.HeaderTimestamp,
+			signature.HeaderNonce,
+			RequestIDHeader,
+		}, ", "),
+		ExposeHeaders: strings.Join([]string{
+			RequestIDHeader,
+			HeaderRateLimitLimit,
+			HeaderRateLimitRemaining,
+			HeaderRateLimitReset,
+			HeaderRateLimitPolicy,
+			fiber.HeaderRetryAfter,
+		}, ", "),
 	}))
 }
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"nobi-assesment/pkg/signature"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCORSAllowsAuthHeaders(t *testing.T) {
+	app := fiber.New()
+	SetupMiddleware(app)
+
+	req := httptest.NewRequest(fiber.MethodOptions, "/api/v1/customers", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := resp.Header.Get(fiber.HeaderAccessControlAllowHeaders)
+	for _, header := range []string{
+		fiber.HeaderAuthorization,
+		HeaderAPIKey,
+		signature.HeaderSignature,
+		signature.HeaderTimestamp,
+		signature.HeaderNonce,
+	} {
+		if !strings.Contains(allowed, header) {
+			t.Errorf("preflight allowed headers %q, missing %s", allowed, header)
+		}
+	}
+}
+
+func TestCORSExposesRateLimitHeaders(t *testing.T) {
+	app := fiber.New()
+	SetupMiddleware(app)
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://app.example.com")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exposed := resp.Header.Get(fiber.HeaderAccessControlExposeHeaders)
+	for _, header := range []string{
+		HeaderRateLimitLimit,
+		HeaderRateLimitRemaining,
+		HeaderRateLimitReset,
+		HeaderRateLimitPolicy,
+		fiber.HeaderRetryAfter,
+	} {
+		if !strings.Contains(exposed, header) {
+			t.Errorf("exposed headers %q, missing %s", exposed, header)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore()
+	now := time.Now()
+
+	if !store.Add("key:n1", now.Add(time.Minute)) {
+		t.Fatal("Add() of a new nonce = false, want true")
+	}
+	if store.Add("key:n1", now.Add(time.Minute)) {
+		t.Error("Add() of a recorded nonce = true, want false")
+	}
+	if !store.Add("other:n1", now.Add(time.Minute)) {
+		t.Error("Add() of the nonce for another key = false, want true")
+	}
+
+	if !store.Add("key:n2", now.Add(-time.Second)) || !store.Add("key:n2", now.Add(time.Minute)) {
+		t.Error("Add() of an expired nonce = false, want true")
+	}
+}
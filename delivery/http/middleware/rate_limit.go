@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"nobi-assesment/internal/domain"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Headers telling clients their budget
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// RateLimit is a token bucket holding up to Requests tokens, refilled evenly
+// over Period. Each request takes a token, so clients may burst up to
+// Requests and then keep to the average rate.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Validate checks that the bucket holds at least one request and refills
+func (l RateLimit) Validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return fmt.Errorf("rate limit of %d requests per %s must allow requests over a positive period", l.Requests, l.Period)
+	}
+	return nil
+}
+
+// refill is the time it takes to add one token to the bucket
+func (l RateLimit) refill() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// RateLimitResult is the state of a bucket after taking a token
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration // Until a token is available, when not allowed
+	Reset      time.Duration // Until the bucket is full again
+}
+
+// RateLimitStore keeps the token buckets of clients. Stores shared between
+// server instances make the budgets hold across them.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimitConfig sets the budgets of read (GET and HEAD) and write routes,
+// which are counted separately
+type RateLimitConfig struct {
+	Read  RateLimit
+	Write RateLimit
+	Store RateLimitStore
+}
+
+// Validate checks both budgets
+func (cfg RateLimitConfig) Validate() error {
+	if err := cfg.Read.Validate(); err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	if err := cfg.Write.Validate(); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+// RateLimiter limits each client to its read and write budgets, answering
+// 429 with Retry-After once a budget is spent. Clients are told their budget
+// in the RateLimit headers. Clients are identified by API key, customer or
+// user, and by IP address when the request is not authenticated.
+func RateLimiter(cfg RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		kind, limit := "write", cfg.Write
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			kind, limit = "read", cfg.Read
+		}
+
+		return takeRateLimit(c, cfg.Store, kind+":"+rateLimitClient(c), limit)
+	}
+}
+
+// IPRateLimiter limits all requests of each IP address to one budget. It
+// runs ahead of authentication, so requests with bad credentials are counted
+// too and credentials cannot be guessed at full speed.
+func IPRateLimiter(limit RateLimit, store RateLimitStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return takeRateLimit(c, store, "ip:"+c.IP(), limit)
+	}
+}
+
+// takeRateLimit takes a token from the bucket of key and continues, or
+// answers 429 when the bucket is empty
+func takeRateLimit(c *fiber.Ctx, store RateLimitStore, key string, limit RateLimit) error {
+	result, err := store.Take(c.UserContext(), key, limit, time.Now())
+	if err != nil {
+		// An unavailable store must not take the API down with it
+		return c.Next()
+	}
+
+	c.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Requests))
+	c.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	c.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Period)))
+	if !result.Allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		return reject(c, fiber.StatusTooManyRequests, domain.ErrRateLimited)
+	}
+
+	return c.Next()
+}
+
+// rateLimitClient identifies who a request counts against
+func rateLimitClient(c *fiber.Ctx) string {
+	principal, ok := domain.PrincipalFromContext(c.UserContext())
+	switch {
+	case !ok:
+		return "ip:" + c.IP()
+	case principal.APIKeyID != "":
+		return "key:" + principal.APIKeyID
+	case principal.CustomerID != "":
+		return "customer:" + principal.CustomerID
+	default:
+		return "user:" + principal.Subject
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// MemoryRateLimitStore keeps buckets in memory, which is enough for a single
+// server instance
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	prunedAt time.Time
+}
+
+type tokenBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	fullAt    time.Time // When the bucket is full again and can be forgotten
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: map[string]*tokenBucket{}}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.prunedAt) >= time.Minute {
+		for k, bucket := range s.buckets {
+			if !now.Before(bucket.fullAt) {
+				delete(s.buckets, k)
+			}
+		}
+		s.prunedAt = now
+	}
+
+	capacity, refill := float64(limit.Requests), limit.refill()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updatedAt: now}
+		s.buckets[key] = bucket
+	}
+	bucket.tokens = math.Min(capacity, bucket.tokens+float64(now.Sub(bucket.updatedAt))/float64(refill))
+	bucket.updatedAt = now
+
+	result := RateLimitResult{Allowed: bucket.tokens >= 1}
+	if result.Allowed {
+		bucket.tokens--
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) * float64(refill))
+	}
+	result.Remaining = int(bucket.tokens)
+	result.Reset = time.Duration((capacity - bucket.tokens) * float64(refill))
+	bucket.fullAt = now.Add(result.Reset)
+
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"nobi-assesment/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	limit := RateLimit{Requests: 3, Period: 3 * time.Second}
+	start := time.Now()
+
+	take := func(key string, at time.Duration) RateLimitResult {
+		t.Helper()
+		result, err := store.Take(context.Background(), key, limit, start.Add(at))
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		return result
+	}
+
+	// The full bucket allows a burst of three
+	for i, want := range []int{2, 1, 0} {
+		result := take("client", 0)
+		if !result.Allowed || result.Remaining != want {
+			t.Fatalf("Take() %d = %+v, want allowed with %d remaining", i, result, want)
+		}
+	}
+
+	result := take("client", 0)
+	if result.Allowed || result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("Take() on an empty bucket = %+v, want retry after 1s and reset in 3s", result)
+	}
+
+	if result := take("other", 0); !result.Allowed {
+		t.Errorf("Take() for another client = %+v, want allowed", result)
+	}
+
+	// One token is back after a second
+	if result := take("client", time.Second); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("Take() after refill = %+v, want allowed with 0 remaining", result)
+	}
+	if result := take("client", time.Second); result.Allowed {
+		t.Errorf("Take() after spending the refill = %+v, want denied", result)
+	}
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   RateLimit
+		wantErr bool
+	}{
+		{"Valid", RateLimit{Requests: 60, Period: time.Minute}, false},
+		{"No requests", RateLimit{Requests: 0, Period: time.Minute}, true},
+		{"Negative requests", RateLimit{Requests: -1, Period: time.Minute}, true},
+		{"No period", RateLimit{Requests: 60}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.limit.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIPRateLimiterCountsRejectedCredentials(t *testing.T) {
+	app := fiber.New()
+	app.Use(IPRateLimiter(RateLimit{Requests: 2, Period: time.Minute}, NewMemoryRateLimitStore()))
+	app.Use(func(c *fiber.Ctx) error {
+		return reject(c, fiber.StatusUnauthorized, domain.ErrUnauthenticated)
+	})
+
+	for i, want := range []int{fiber.StatusUnauthorized, fiber.StatusUnauthorized, fiber.StatusTooManyRequests} {
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/customers", nil))
+		if err != nil {
+			t.Fatalf("Test() error = %v", err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("request %d status = %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"nobi-assesment/internal/domain"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:embed default_policy.json
+var defaultPolicy []byte
+
+// Policy grants permissions to roles. Permissions are named
+// "resource:action"; "resource:*" grants every action on a resource and "*"
+// everything.
+type Policy struct {
+	Roles map[string][]string `json:"roles"`
+}
+
+// LoadPolicy reads a policy file, or the built-in policy when path is empty
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultPolicy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := &Policy{}
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, fmt.Errorf("parse policy: %w", err)
+	}
+	if len(policy.Roles) == 0 {
+		return nil, fmt.Errorf("policy grants no roles")
+	}
+	return policy, nil
+}
+
+// Allows reports whether any of the roles is granted the permission
+func (p *Policy) Allows(roles []string, permission string) bool {
+	for _, role := range roles {
+		if grants(p.Roles[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// Holds reports whether the principal is granted the permission, through its
+// roles or directly as API keys are
+func (p *Policy) Holds(principal *domain.Principal, permission string) bool {
+	return p.Allows(principal.Roles, permission) || grants(principal.Permissions, permission)
+}
+
+// grants reports whether the permission is among the granted ones
+func grants(granted []string, permission string) bool {
+	resource, _, _ := strings.Cut(permission, ":")
+	for _, g := range granted {
+		if g == permission || g == "*" || g == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// Group checks the permissions of a route group: "resource:read" for GET
+// and HEAD requests and "resource:write" for anything else
+func (p *Policy) Group(resource string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		action := "write"
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			action = "read"
+		}
+		return p.check(c, resource+":"+action)
+	}
+}
+
+// Require checks a permission on top of the one of the route group, for
+// routes restricted further than the rest of their group
+func (p *Policy) Require(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return p.check(c, permission)
+	}
+}
+
+// check rejects principals lacking the permission, through their roles or
+// granted directly as to API keys. Requests without a principal are only
+// possible when authentication is disabled and are let through.
+func (p *Policy) check(c *fiber.Ctx, permission string) error {
+	principal, ok := domain.PrincipalFromContext(c.UserContext())
+	if ok && !p.Holds(principal, permission) {
+		return reject(c, fiber.StatusForbidden, domain.ErrForbidden.WithMessage("missing permission "+permission))
+	}
+	return c.Next()
+}
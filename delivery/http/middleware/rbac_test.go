@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"nobi-assesment/internal/domain"
+	"testing"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		roles      []string
+		permission string
+		want       bool
+	}{
+		{"Customer transacts", []string{domain.RoleCustomer}, "transactions:write", true},
+		{"Customer creates investment", []string{domain.RoleCustomer}, "investments:write", false},
+		{"Customer deactivates", []string{domain.RoleCustomer}, "customers:manage", false},
+		{"Operator by wildcard", []string{domain.RoleOperator}, "customers:manage", true},
+		{"Operator reverses", []string{domain.RoleOperator}, "transactions:reverse", false},
+		{"Fund admin reverses", []string{domain.RoleFundAdmin}, "transactions:reverse", true},
+		{"Operator funds wallets", []string{domain.RoleOperator}, "wallets:fund", true},
+		{"Customer funds wallets", []string{domain.RoleCustomer}, "wallets:fund", false},
+		{"Any of the roles", []string{domain.RoleAuditor, domain.RoleFundAdmin}, "investments:write", true},
+		{"Auditor writes", []string{domain.RoleAuditor}, "transactions:write", false},
+		{"Unknown role", []string{"guest"}, "investments:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Allows(tt.roles, tt.permission); got != tt.want {
+				t.Errorf("Allows(%v, %q) = %v, want %v", tt.roles, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyHolds(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+	operator := &domain.Principal{Subject: "operator", Roles: []string{domain.RoleOperator}}
+	apiKey := &domain.Principal{Subject: "api-key:k1", Permissions: []string{"transactions:write"}}
+
+	tests := []struct {
+		name       string
+		principal  *domain.Principal
+		permission string
+		want       bool
+	}{
+		{"Through a role", operator, "transactions:write", true},
+		{"Resource wildcard through a role", operator, "customers:*", true},
+		{"Everything", operator, "*", false},
+		{"Not granted to the role", operator, "transactions:reverse", false},
+		{"Granted directly", apiKey, "transactions:write", true},
+		{"Not granted directly", apiKey, "transactions:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Holds(tt.principal, tt.permission); got != tt.want {
+				t.Errorf("Holds(%s, %q) = %v, want %v", tt.principal.Subject, tt.permission, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/metrics"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/utils"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestIDHeader carries the ID of a request
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits client supplied request IDs to values that are
+// safe to store and log
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// RequestInfo carries the ID and client address of each request in its
+// context, where logs pick up the ID. The ID is taken from the X-Request-ID
+// header when it holds a usable value, generated otherwise, and sent back in
+// the response.
+func RequestInfo() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = utils.GenerateUUID()
+		}
+		c.Set(RequestIDHeader, id)
+
+		ctx := domain.WithRequestInfo(c.UserContext(), domain.RequestInfo{ID: id, IP: c.IP()})
+		c.SetUserContext(logger.WithAttrs(ctx, slog.String("request_id", id)))
+		return c.Next()
+	}
+}
+
+// LogRequests logs every request once it is answered, at warn level for
+// server errors
+func LogRequests() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		// Errors are answered here rather than after the chain returns, so
+		// the logged status is the one sent
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelWarn
+		}
+		slog.Log(c.UserContext(), level, "request",
+			"method", c.Method(),
+			"path", c.Path(),
+			"route", c.Route().Path,
+			"status", status,
+			"latency", time.Since(start),
+			"ip", c.IP())
+		return nil
+	}
+}
+
+// RecordMetrics observes the time taken to answer every request by its
+// method, route and status, and counts error responses by their code. Like
+// LogRequests it answers errors itself, so the recorded status is the one
+// sent.
+func RecordMetrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		metrics.ObserveRequest(c.Method(), c.Route().Path, status, time.Since(start))
+		if status >= fiber.StatusBadRequest {
+			metrics.RecordFailure(errorCode(c.Response().Body(), status))
+		}
+		return nil
+	}
+}
+
+// errorCode reads the code of an error response, or HTTP_<status> for errors
+// sent without one
+func errorCode(body []byte, status int) string {
+	var response struct {
+		Code string `json:"code"`
+	}
+	if json.Unmarshal(body, &response) == nil && response.Code != "" {
+		return response.Code
+	}
+	return "HTTP_" + strconv.Itoa(status)
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import "testing"
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		body   string
+		status int
+		want   string
+	}{
+		{`{"error":"Rate limit exceeded","code":"RATE_LIMITED"}`, 429, "RATE_LIMITED"},
+		{`{"error":"Cannot parse JSON"}`, 400, "HTTP_400"},
+		{`Cannot GET /nowhere`, 404, "HTTP_404"},
+		{``, 500, "HTTP_500"},
+	}
+
+	for _, tt := range tests {
+		if got := errorCode([]byte(tt.body), tt.status); got != tt.want {
+			t.Errorf("errorCode(%q, %d) = %q, want %q", tt.body, tt.status, got, tt.want)
+		}
+	}
+}
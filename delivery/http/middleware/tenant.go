@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ResolveTenant scopes the request to the home tenant of its principal, or
+// for platform admins and unauthenticated requests to the tenant serving the
+// Host header, falling back to the default tenant. Repositories only reach
+// the records of the tenant in the request context.
+func ResolveTenant(tenantUsecase usecase.TenantUsecase) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var principalTenantID string
+		if principal, ok := domain.PrincipalFromContext(c.UserContext()); ok {
+			principalTenantID = principal.HomeTenant()
+		}
+
+		tenant, err := tenantUsecase.Resolve(c.UserContext(), principalTenantID, c.Hostname())
+		if err != nil {
+			if e, ok := domain.AsError(err); ok {
+				return reject(c, fiber.StatusForbidden, e)
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to resolve tenant"})
+		}
+
+		ctx := domain.WithTenant(c.UserContext(), tenant.ID)
+		c.SetUserContext(logger.WithAttrs(ctx, slog.String("tenant_id", tenant.ID)))
+		return c.Next()
+	}
+}
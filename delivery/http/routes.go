@@ -2,21 +2,30 @@ package http
 
 import (
 	"nobi-assesment/delivery/http/handler"
+	"nobi-assesment/delivery/http/middleware"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 // SetupRoutes configures all the routes for the API
 func SetupRoutes(
 	app *fiber.App,
+	policy *middleware.Policy,
 	customerHandler *handler.CustomerHandler,
 	investmentHandler *handler.InvestmentHandler,
 	transactionHandler *handler.TransactionHandler,
+	riskProfileHandler *handler.RiskProfileHandler,
+	recurringPlanHandler *handler.RecurringPlanHandler,
+	walletHandler *handler.WalletHandler,
+	ledgerHandler *handler.LedgerHandler,
+	statementHandler *handler.StatementHandler,
+	importHandler *handler.ImportHandler,
+	orderBatchHandler *handler.OrderBatchHandler,
+	exportHandler *handler.ExportHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	tenantHandler *handler.TenantHandler,
+	auditHandler *handler.AuditHandler,
 ) {
-	// Middleware
-	app.Use(logger.New())
-
 	// Health check endpoint to verify the API is running and DB connection
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -25,27 +34,96 @@ func SetupRoutes(
 		})
 	})
 
-	// API routes
+	// API routes. Each group checks the read or write permission of its
+	// resource against the access policy, and sensitive routes a permission of
+	// their own. Customers and scoped API keys may only reach the data of
+	// their customers, checked by ownCustomer on customer ID parameters, by
+	// the handlers for IDs sent in the body or owned by a fetched record, and
+	// by unscoped on routes spanning every customer.
 	api := app.Group("/api")
+	ownCustomer := middleware.RequireOwnCustomer("id")
+	unscoped := middleware.RequireUnscoped()
 
 	// Customer routes
-	customers := api.Group("/customers")
-	customers.Post("/", customerHandler.Create)
-	customers.Get("/", customerHandler.GetAll)
-	customers.Get("/:id", customerHandler.GetByID)
+	customers := api.Group("/customers", policy.Group("customers"))
+	customers.Post("/", policy.Require("customers:create"), unscoped, customerHandler.Create)
+	customers.Get("/", policy.Require("customers:list"), unscoped, customerHandler.GetAll)
+	customers.Get("/:id", ownCustomer, customerHandler.GetByID)
+	customers.Patch("/:id", ownCustomer, customerHandler.Update)
+	customers.Post("/:id/deactivate", policy.Require("customers:manage"), ownCustomer, customerHandler.Deactivate)
+	customers.Post("/:id/reactivate", policy.Require("customers:manage"), ownCustomer, customerHandler.Reactivate)
+	customers.Post("/:id/kyc", policy.Require("customers:manage"), ownCustomer, customerHandler.UpdateKYCStatus)
+	customers.Get("/:id/risk-profile", ownCustomer, riskProfileHandler.GetCurrent)
+	customers.Post("/:id/risk-profile", ownCustomer, riskProfileHandler.Submit)
+	customers.Get("/:id/wallet", ownCustomer, walletHandler.Get)
+	customers.Post("/:id/wallet/top-up", policy.Require("wallets:fund"), ownCustomer, walletHandler.TopUp)
+	customers.Post("/:id/wallet/payout", ownCustomer, walletHandler.Payout)
+	customers.Get("/:id/wallet/movements", ownCustomer, walletHandler.GetMovements)
+	customers.Get("/:id/statements", ownCustomer, statementHandler.Generate)
+
+	// Risk questionnaire route
+	api.Get("/risk-questionnaire", riskProfileHandler.GetQuestionnaire)
 
 	// Investment routes
-	investments := api.Group("/investments")
+	investments := api.Group("/investments", policy.Group("investments"))
 	investments.Post("/", investmentHandler.Create)
 	investments.Get("/", investmentHandler.GetAll)
 	investments.Get("/:id", investmentHandler.GetByID)
+	investments.Patch("/:id", investmentHandler.Update)
 
-	// Transaction routes
-	transactions := api.Group("/transactions")
+	// Transaction routes. Reversals need transactions:reverse instead of the
+	// write permission of the group, so they are registered ahead of it.
+	api.Post("/transactions/:id/reverse", policy.Require("transactions:reverse"), transactionHandler.Reverse)
+	transactions := api.Group("/transactions", policy.Group("transactions"))
 	transactions.Post("/deposit", transactionHandler.Deposit)
 	transactions.Post("/withdraw", transactionHandler.Withdraw)
-	transactions.Get("/customer/:id", transactionHandler.GetCustomerTransactions)
+	transactions.Post("/batch", policy.Require("transactions:batch"), unscoped, orderBatchHandler.Process)
+	transactions.Get("/customer/:id", ownCustomer, transactionHandler.GetCustomerTransactions)
+	transactions.Get("/:id", transactionHandler.GetTransaction)
+
+	// Recurring plan routes
+	recurringPlans := api.Group("/recurring-plans", policy.Group("recurring_plans"))
+	recurringPlans.Post("/", recurringPlanHandler.Create)
+	recurringPlans.Get("/", recurringPlanHandler.GetAll)
+	recurringPlans.Get("/:id", recurringPlanHandler.GetByID)
+	recurringPlans.Patch("/:id", recurringPlanHandler.Update)
+	recurringPlans.Delete("/:id", recurringPlanHandler.Cancel)
+	recurringPlans.Post("/:id/pause", recurringPlanHandler.Pause)
+	recurringPlans.Post("/:id/resume", recurringPlanHandler.Resume)
+	recurringPlans.Post("/:id/skip", recurringPlanHandler.Skip)
+	recurringPlans.Get("/:id/runs", recurringPlanHandler.GetRuns)
+
+	// Ledger routes
+	ledger := api.Group("/ledger", policy.Group("ledger"), unscoped)
+	ledger.Get("/entries", ledgerHandler.GetEntries)
+	ledger.Get("/verify", ledgerHandler.Verify)
+
+	// Bulk import route
+	api.Post("/imports/:entity", policy.Require("imports:write"), unscoped, importHandler.Import)
+
+	// Export route
+	api.Get("/exports/:dataset", policy.Require("exports:read"), unscoped, exportHandler.Export)
+
+	// API key routes
+	apiKeys := api.Group("/api-keys", policy.Group("api_keys"), middleware.RejectAPIKeys())
+	apiKeys.Post("/", apiKeyHandler.Issue)
+	apiKeys.Get("/", apiKeyHandler.GetAll)
+	apiKeys.Get("/:id", apiKeyHandler.GetByID)
+	apiKeys.Post("/:id/rotate", apiKeyHandler.Rotate)
+	apiKeys.Post("/:id/revoke", apiKeyHandler.Revoke)
+
+	// Tenant routes
+	tenants := api.Group("/tenants", policy.Group("tenants"), middleware.RejectAPIKeys())
+	tenants.Post("/", tenantHandler.Create)
+	tenants.Get("/", tenantHandler.GetAll)
+	tenants.Get("/:id", tenantHandler.GetByID)
+	tenants.Patch("/:id", tenantHandler.Update)
+
+	// Audit routes
+	audit := api.Group("/audit", policy.Group("audit"), unscoped)
+	audit.Get("/", auditHandler.GetAll)
+	audit.Get("/verify", auditHandler.Verify)
 
 	// Portfolio route
-	api.Get("/portfolio/:customer_id/:investment_id", transactionHandler.GetCustomerPortfolio)
+	api.Get("/portfolio/:customer_id/:investment_id", policy.Require("transactions:read"), middleware.RequireOwnCustomer("customer_id"), transactionHandler.GetCustomerPortfolio)
 }
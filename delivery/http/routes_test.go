@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"net/http/httptest"
+	"nobi-assesment/delivery/http/handler"
+	"nobi-assesment/delivery/http/middleware"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeTransactionUsecase struct {
+	usecase.TransactionUsecase
+}
+
+func (fakeTransactionUsecase) Reverse(ctx context.Context, id string, req *domain.ReverseTransactionRequest) (*domain.Transaction, error) {
+	return &domain.Transaction{ID: "reversal", ReversesID: id}, nil
+}
+
+// newTestApp serves the routes to requests made by the principal, with the
+// built-in policy
+func newTestApp(t *testing.T, principal *domain.Principal) *fiber.App {
+	t.Helper()
+	policy, err := middleware.LoadPolicy("")
+	if err != nil {
+		t.Fatalf("LoadPolicy() error = %v", err)
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), principal))
+		return c.Next()
+	})
+	SetupRoutes(app, policy, nil, nil, handler.NewTransactionHandler(fakeTransactionUsecase{}),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	return app
+}
+
+func TestRoutePermissions(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *domain.Principal
+		path      string
+		want      int
+	}{
+		{
+			"Fund admin reverses",
+			&domain.Principal{Subject: "admin", Roles: []string{domain.RoleFundAdmin}},
+			"/api/transactions/t1/reverse",
+			fiber.StatusCreated,
+		},
+		{
+			"Operator reverses",
+			&domain.Principal{Subject: "operator", Roles: []string{domain.RoleOperator}},
+			"/api/transactions/t1/reverse",
+			fiber.StatusForbidden,
+		},
+		{
+			"Customer tops up own wallet",
+			&domain.Principal{Subject: "c1", CustomerID: "c1", Roles: []string{domain.RoleCustomer}},
+			"/api/customers/c1/wallet/top-up",
+			fiber.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, tt.path, strings.NewReader(`{"amount": 1000}`))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			resp, err := newTestApp(t, tt.principal).Test(req)
+			if err != nil {
+				t.Fatalf("Test() error = %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("POST %s status = %d, want %d", tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIKey authenticates a partner calling the API server to server. Only a
+// hash of the key is stored; the key itself is shown once when issued.
+type APIKey struct {
+	ID               string     `json:"id"`
+	TenantID         string     `json:"tenant_id"` // Tenant the key was issued in and acts within
+	Name             string     `json:"name"`
+	Prefix           string     `json:"prefix"` // Public part of the key it is looked up by
+	KeyHash          string     `json:"-"`
+	Permissions      []string   `json:"permissions"`               // Operations the key may perform, named like policy permissions
+	CustomerIDs      []string   `json:"customer_ids"`              // Customers the key may act on, empty for every customer
+	RequireSignature bool       `json:"require_signature"`         // Transaction requests must be signed
+	RotatedFromID    string     `json:"rotated_from_id,omitempty"` // Key this one replaced
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// IsActive reports whether the key can authenticate requests at now
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// Principal returns the caller a request authenticated with the key acts as
+func (k *APIKey) Principal() *Principal {
+	return &Principal{
+		Subject:           "api-key:" + k.ID,
+		TenantID:          k.TenantID,
+		APIKeyID:          k.ID,
+		Permissions:       k.Permissions,
+		CustomerIDs:       k.CustomerIDs,
+		SignatureRequired: k.RequireSignature,
+	}
+}
+
+// IssuedAPIKey is a newly issued key together with its secret values, which
+// cannot be retrieved later
+type IssuedAPIKey struct {
+	*APIKey
+	Key           string `json:"key"`                      // Sent in the X-API-Key header
+	SigningSecret string `json:"signing_secret,omitempty"` // Secret for signing requests, returned only when the key is issued or rotated
+}
+
+type CreateAPIKeyRequest struct {
+	Name             string     `json:"name"`
+	Permissions      []string   `json:"permissions"`
+	CustomerIDs      []string   `json:"customer_ids"`
+	ExpiresAt        *time.Time `json:"expires_at"`
+	RequireSignature bool       `json:"require_signature"`
+}
+
+// PermissionResources are the resources permissions can be granted on
+var PermissionResources = []string{
+	"customers", "wallets", "investments", "transactions", "recurring_plans",
+	"ledger", "imports", "exports", "api_keys", "tenants", "audit",
+}
+
+// Validate checks the name and that permissions are named "resource:action"
+// after a known resource
+func (r *CreateAPIKeyRequest) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrAPIKeyNameRequired
+	}
+	if len(r.Permissions) == 0 {
+		return ErrInvalidAPIKeyPermissions
+	}
+	for _, permission := range r.Permissions {
+		resource, action, ok := strings.Cut(permission, ":")
+		if permission != "*" && (!ok || resource == "" || action == "") {
+			return ErrInvalidAPIKeyPermissions.WithMessage("invalid permission " + permission)
+		}
+		if permission != "*" && !slices.Contains(PermissionResources, resource) {
+			return ErrInvalidAPIKeyPermissions.WithMessage("unknown resource " + resource)
+		}
+	}
+	if slices.Contains(r.CustomerIDs, "") {
+		return ErrInvalidAPIKeyPermissions.WithMessage("customer IDs cannot be empty")
+	}
+	return nil
+}
+
+// RotateAPIKeyRequest sets how long the replaced key keeps working, as a
+// duration such as "24h". No overlap expires it at once.
+type RotateAPIKeyRequest struct {
+	Overlap string `json:"overlap"`
+}
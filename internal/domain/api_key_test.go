@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCreateAPIKeyRequestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		wantErr     bool
+	}{
+		{"Resource actions", []string{"transactions:write", "customers:read"}, false},
+		{"Resource wildcard", []string{"investments:*"}, false},
+		{"Everything", []string{"*"}, false},
+		{"None", nil, true},
+		{"Missing action", []string{"transactions"}, true},
+		{"Missing resource", []string{":write"}, true},
+		{"Unknown resource", []string{"payroll:read"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateAPIKeyRequest{Name: "partner", Permissions: tt.permissions}
+			err := req.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidAPIKeyPermissions) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidAPIKeyPermissions)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	AuditEntityCustomer    = "CUSTOMER"
+	AuditEntityInvestment  = "INVESTMENT"
+	AuditEntityTransaction = "TRANSACTION"
+
+	AuditActionCustomerCreated     = "CUSTOMER_CREATED"
+	AuditActionCustomerUpdated     = "CUSTOMER_UPDATED"
+	AuditActionCustomerDeactivated = "CUSTOMER_DEACTIVATED"
+	AuditActionCustomerReactivated = "CUSTOMER_REACTIVATED"
+	AuditActionCustomerKYCUpdated  = "CUSTOMER_KYC_UPDATED"
+
+	AuditActionInvestmentCreated = "INVESTMENT_CREATED"
+	AuditActionInvestmentUpdated = "INVESTMENT_UPDATED"
+
+	AuditActionDepositCreated      = "DEPOSIT_CREATED"
+	AuditActionWithdrawalCreated   = "WITHDRAWAL_CREATED"
+	AuditActionTransactionReversed = "TRANSACTION_REVERSED"
+	AuditActionTransactionSettled  = "TRANSACTION_SETTLED"
+)
+
+// AuditActorSystem is the actor of changes made outside a request, such as
+// by workers and command line tools
+const AuditActorSystem = "system"
+
+// MaxAuditLimit caps the logs returned by one audit query
+const MaxAuditLimit = 1000
+
+// AuditLog records a change made to an entity. Before and After hold JSON
+// snapshots of the entity around the change. The logs of a tenant form a
+// hash chain: each hash covers the log and the hash of the one before it, so
+// editing or removing a log breaks every hash after it.
+type AuditLog struct {
+	ID         string          `json:"id"`
+	Sequence   int64           `json:"sequence"` // Position in the tenant's chain, from 1
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Reason     string          `json:"reason,omitempty"`
+	Actor      string          `json:"actor"` // Subject of the principal making the change, or system
+	RequestID  string          `json:"request_id,omitempty"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	PrevHash   string          `json:"prev_hash"` // Empty for the first log of a tenant
+	Hash       string          `json:"hash"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ComputeHash returns the hex SHA-256 of the log's fields and the hash of
+// the log before it
+func (l *AuditLog) ComputeHash() string {
+	// Encoding the fields as a JSON array keeps their boundaries unambiguous
+	fields, _ := json.Marshal([]string{
+		l.PrevHash,
+		strconv.FormatInt(l.Sequence, 10),
+		l.ID,
+		l.EntityType,
+		l.EntityID,
+		l.Action,
+		l.Reason,
+		l.Actor,
+		l.RequestID,
+		l.IPAddress,
+		string(l.Before),
+		string(l.After),
+		l.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditLogFilter selects audit logs. Empty fields match every log.
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	Action     string
+	From       time.Time // Logs created at or after, when set
+	To         time.Time // Logs created before, when set
+	Limit      int
+}
+
+// Validate checks the time range is ordered and the limit is within bounds
+func (f *AuditLogFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return ErrInvalidAuditFilter.WithMessage("from must be before to")
+	}
+	if f.Limit < 1 || f.Limit > MaxAuditLimit {
+		return ErrInvalidAuditFilter.WithMessage("limit must be between 1 and " + strconv.Itoa(MaxAuditLimit))
+	}
+	return nil
+}
+
+// AuditVerification is the result of checking a tenant's hash chain
+type AuditVerification struct {
+	Valid      bool      `json:"valid"`
+	Entries    int64     `json:"entries"`
+	BrokenAt   int64     `json:"broken_at,omitempty"` // Sequence of the first log failing the check
+	Problem    string    `json:"problem,omitempty"`
+	VerifiedAt time.Time `json:"verified_at"`
+}
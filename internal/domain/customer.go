@@ -1,9 +1,38 @@
 package domain
 
 type Customer struct {
-	ID       string  `json:"id"`
-	Name     string  `json:"name"`
-	Balance  float64 `json:"balance"`
-	Units    float64 `json:"units"`
-	IsActive bool    `json:"is_active"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	Phone         string    `json:"phone"`
+	IDNumber      string    `json:"id_number"`
+	DateOfBirth   string    `json:"date_of_birth"` // YYYY-MM-DD
+	Address       string    `json:"address"`
+	KYCStatus     KYCStatus `json:"kyc_status"`
+	Balance       float64   `json:"balance"`        // Cash, pending cash and invested value combined
+	CashBalance   float64   `json:"cash_balance"`   // Wallet cash available
+	PendingCash   float64   `json:"pending_cash"`   // Redemption proceeds awaiting settlement
+	InvestedValue float64   `json:"invested_value"` // Holdings valued at the current NAB
+	Units         float64   `json:"units"`
+	IsActive      bool      `json:"is_active"`
+}
+
+// UpdateCustomerRequest holds the editable profile fields of a customer.
+// Nil fields are left untouched, empty strings clear optional fields.
+type UpdateCustomerRequest struct {
+	Name        *string `json:"name"`
+	Email       *string `json:"email"`
+	Phone       *string `json:"phone"`
+	IDNumber    *string `json:"id_number"`
+	DateOfBirth *string `json:"date_of_birth"`
+	Address     *string `json:"address"`
+}
+
+type CustomerStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+type KYCStatusRequest struct {
+	Status KYCStatus `json:"status"`
+	Reason string    `json:"reason"`
 }
@@ -0,0 +1,164 @@
+package domain
+
+import "errors"
+
+// ErrorKind classifies a domain error so the delivery layer can map it to a
+// transport status without knowing about individual error codes.
+type ErrorKind int
+
+const (
+	KindInvalid ErrorKind = iota + 1
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+	KindUnauthenticated
+	KindForbidden
+	KindRateLimited
+)
+
+// Error is a domain error carrying a stable, machine readable code.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is reports errors with the same code as equal, so callers can use
+// errors.Is against the sentinel values below.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithMessage returns a copy of e with a more specific message, keeping its
+// kind and code.
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: message}
+}
+
+// NewError creates a new domain error
+func NewError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// AsError extracts a domain error from err, if any.
+func AsError(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
+
+var (
+	ErrCustomerNotFound        = NewError(KindNotFound, "CUSTOMER_NOT_FOUND", "customer not found")
+	ErrCustomerAlreadyActive   = NewError(KindConflict, "CUSTOMER_ALREADY_ACTIVE", "customer is already active")
+	ErrCustomerAlreadyInactive = NewError(KindConflict, "CUSTOMER_ALREADY_INACTIVE", "customer is already inactive")
+	ErrReasonRequired          = NewError(KindInvalid, "REASON_REQUIRED", "reason is required")
+	ErrCustomerNameRequired    = NewError(KindInvalid, "CUSTOMER_NAME_REQUIRED", "customer name is required")
+	ErrCustomerNameTaken       = NewError(KindConflict, "CUSTOMER_NAME_TAKEN", "customer name must be unique")
+	ErrCustomerEmailTaken      = NewError(KindConflict, "CUSTOMER_EMAIL_TAKEN", "customer email is already registered")
+
+	ErrInvalidEmail       = NewError(KindInvalid, "INVALID_EMAIL", "email format is invalid")
+	ErrInvalidPhone       = NewError(KindInvalid, "INVALID_PHONE", "phone must contain 8 to 15 digits with an optional leading +")
+	ErrInvalidIDNumber    = NewError(KindInvalid, "INVALID_ID_NUMBER", "id number must be 6 to 32 alphanumeric characters")
+	ErrInvalidDateOfBirth = NewError(KindInvalid, "INVALID_DATE_OF_BIRTH", "date of birth must be a past date formatted as YYYY-MM-DD")
+
+	ErrInvalidKYCStatus     = NewError(KindInvalid, "INVALID_KYC_STATUS", "kyc status must be one of UNVERIFIED, PENDING, VERIFIED, REJECTED")
+	ErrInvalidKYCTransition = NewError(KindConflict, "INVALID_KYC_TRANSITION", "kyc status transition is not allowed")
+	ErrKYCProfileIncomplete = NewError(KindUnprocessable, "KYC_PROFILE_INCOMPLETE", "id number, date of birth and address are required for kyc verification")
+	ErrKYCRequired          = NewError(KindUnprocessable, "KYC_REQUIRED", "customer must be kyc verified to deposit this amount")
+
+	ErrInvestmentNotFound          = NewError(KindNotFound, "INVESTMENT_NOT_FOUND", "investment product not found")
+	ErrInvestmentNameRequired      = NewError(KindInvalid, "INVESTMENT_NAME_REQUIRED", "investment product name is required")
+	ErrInvalidRiskLevel            = NewError(KindInvalid, "INVALID_RISK_LEVEL", "risk level must be one of LOW, MEDIUM, HIGH")
+	ErrInvalidFundCategory         = NewError(KindInvalid, "INVALID_FUND_CATEGORY", "category must be one of MONEY_MARKET, BOND, EQUITY, MIXED")
+	ErrInvalidCurrency             = NewError(KindInvalid, "INVALID_CURRENCY", "currency must be a three letter ISO 4217 code")
+	ErrInvalidInceptionDate        = NewError(KindInvalid, "INVALID_INCEPTION_DATE", "inception date must be formatted as YYYY-MM-DD")
+	ErrInvalidInvestmentStatus     = NewError(KindInvalid, "INVALID_INVESTMENT_STATUS", "status must be one of OPEN, SUSPENDED, CLOSED")
+	ErrInvalidInvestmentTransition = NewError(KindConflict, "INVALID_INVESTMENT_TRANSITION", "investment status transition is not allowed")
+	ErrInvestmentNotOpen           = NewError(KindUnprocessable, "INVESTMENT_NOT_OPEN", "investment product is not open for deposits")
+	ErrInvestmentSuspended         = NewError(KindUnprocessable, "INVESTMENT_SUSPENDED", "investment product is suspended")
+
+	ErrIncompleteRiskAnswers       = NewError(KindInvalid, "INCOMPLETE_RISK_ANSWERS", "every risk questionnaire question must be answered exactly once")
+	ErrInvalidRiskAnswer           = NewError(KindInvalid, "INVALID_RISK_ANSWER", "risk questionnaire answer refers to an unknown question or option")
+	ErrRiskProfileNotFound         = NewError(KindNotFound, "RISK_PROFILE_NOT_FOUND", "customer has no risk profile")
+	ErrRiskProfileRequired         = NewError(KindUnprocessable, "RISK_PROFILE_REQUIRED", "customer must complete the risk questionnaire before investing")
+	ErrRiskProfileExpired          = NewError(KindUnprocessable, "RISK_PROFILE_EXPIRED", "customer risk profile has expired, the risk questionnaire must be completed again")
+	ErrRiskNotSuitable             = NewError(KindUnprocessable, "RISK_NOT_SUITABLE", "investment risk level exceeds the customer risk profile")
+	ErrRiskAcknowledgementRequired = NewError(KindUnprocessable, "RISK_ACKNOWLEDGEMENT_REQUIRED", "investment risk level exceeds the customer risk profile, set risk_acknowledged to proceed")
+
+	ErrBelowMinInitialSubscription    = NewError(KindUnprocessable, "BELOW_MIN_INITIAL_SUBSCRIPTION", "amount is below the minimum initial subscription")
+	ErrBelowMinSubsequentSubscription = NewError(KindUnprocessable, "BELOW_MIN_SUBSEQUENT_SUBSCRIPTION", "amount is below the minimum subsequent subscription")
+	ErrBelowMinRedemption             = NewError(KindUnprocessable, "BELOW_MIN_REDEMPTION", "amount is below the minimum redemption")
+	ErrBelowMinRemainingBalance       = NewError(KindUnprocessable, "BELOW_MIN_REMAINING_BALANCE", "remaining balance would fall below the minimum, redeem the full holding instead")
+	ErrMaxHoldingExceeded             = NewError(KindUnprocessable, "MAX_HOLDING_EXCEEDED", "deposit would exceed the maximum holding per customer")
+	ErrDailySubscriptionLimitExceeded = NewError(KindUnprocessable, "DAILY_SUBSCRIPTION_LIMIT_EXCEEDED", "deposit would exceed the daily subscription limit")
+	ErrDailyRedemptionLimitExceeded   = NewError(KindUnprocessable, "DAILY_REDEMPTION_LIMIT_EXCEEDED", "withdrawal would exceed the daily redemption limit")
+	ErrInvalidInvestmentRules         = NewError(KindInvalid, "INVALID_INVESTMENT_RULES", "investment rule amounts cannot be negative")
+
+	ErrRecurringPlanNotFound  = NewError(KindNotFound, "RECURRING_PLAN_NOT_FOUND", "recurring plan not found")
+	ErrInvalidPlanAmount      = NewError(KindInvalid, "INVALID_PLAN_AMOUNT", "recurring plan amount must be greater than zero")
+	ErrInvalidPlanFrequency   = NewError(KindInvalid, "INVALID_PLAN_FREQUENCY", "frequency must be one of DAILY, WEEKLY, MONTHLY")
+	ErrInvalidPlanDates       = NewError(KindInvalid, "INVALID_PLAN_DATES", "start and end dates must be formatted as YYYY-MM-DD and the end date cannot be before the start date")
+	ErrInvalidPlanTransition  = NewError(KindConflict, "INVALID_PLAN_TRANSITION", "recurring plan status transition is not allowed")
+	ErrRecurringPlanNotActive = NewError(KindConflict, "RECURRING_PLAN_NOT_ACTIVE", "recurring plan is not active")
+	ErrRecurringPlanRunning   = NewError(KindConflict, "RECURRING_PLAN_RUNNING", "recurring plan is being run, try again shortly")
+
+	ErrInvalidWalletAmount = NewError(KindInvalid, "INVALID_WALLET_AMOUNT", "amount must be greater than zero")
+	ErrInsufficientFunds   = NewError(KindUnprocessable, "INSUFFICIENT_FUNDS", "wallet balance is insufficient")
+	ErrCustomerNotActive   = NewError(KindUnprocessable, "CUSTOMER_NOT_ACTIVE", "customer is not active")
+
+	ErrUnbalancedJournalEntry = NewError(KindUnprocessable, "UNBALANCED_JOURNAL_ENTRY", "journal entry does not balance")
+
+	ErrTransactionNotFound        = NewError(KindNotFound, "TRANSACTION_NOT_FOUND", "transaction not found")
+	ErrTransactionAlreadyReversed = NewError(KindConflict, "TRANSACTION_ALREADY_REVERSED", "transaction has already been reversed")
+	ErrTransactionNotReversible   = NewError(KindConflict, "TRANSACTION_NOT_REVERSIBLE", "only pending or completed deposits and withdrawals can be reversed")
+	ErrReversalUnitsUnavailable   = NewError(KindUnprocessable, "REVERSAL_UNITS_UNAVAILABLE", "customer no longer holds the units the deposit bought")
+
+	ErrInvalidStatementPeriod = NewError(KindInvalid, "INVALID_STATEMENT_PERIOD", "from and to must be formatted as YYYY-MM-DD and to cannot be before from")
+	ErrInvalidStatementFormat = NewError(KindInvalid, "INVALID_STATEMENT_FORMAT", "format must be one of csv, pdf")
+
+	ErrInvalidImportEntity = NewError(KindInvalid, "INVALID_IMPORT_ENTITY", "entity must be one of customers, investments")
+	ErrInvalidImportFormat = NewError(KindInvalid, "INVALID_IMPORT_FORMAT", "format must be one of csv, jsonl")
+	ErrInvalidImportFile   = NewError(KindInvalid, "INVALID_IMPORT_FILE", "import file could not be read")
+	ErrInvalidImportRow    = NewError(KindInvalid, "INVALID_IMPORT_ROW", "row could not be parsed")
+
+	ErrDuplicateExternalReference = NewError(KindConflict, "DUPLICATE_EXTERNAL_REFERENCE", "an order with this external reference was already processed")
+	ErrExternalReferenceRequired  = NewError(KindInvalid, "EXTERNAL_REFERENCE_REQUIRED", "external reference is required")
+	ErrInvalidOrderType           = NewError(KindInvalid, "INVALID_ORDER_TYPE", "type must be one of DEPOSIT, WITHDRAW")
+	ErrInvalidOrderQuantity       = NewError(KindInvalid, "INVALID_ORDER_QUANTITY", "deposits need an amount, withdrawals an amount or units")
+
+	ErrInvalidExportDataset = NewError(KindInvalid, "INVALID_EXPORT_DATASET", "dataset must be one of transactions, holdings, nab_history")
+	ErrInvalidExportFormat  = NewError(KindInvalid, "INVALID_EXPORT_FORMAT", "format must be one of csv, jsonl, parquet")
+	ErrInvalidExportSince   = NewError(KindInvalid, "INVALID_EXPORT_SINCE", "since must be an RFC 3339 time or a YYYY-MM-DD date")
+
+	ErrUnauthenticated = NewError(KindUnauthenticated, "UNAUTHENTICATED", "a valid bearer token is required")
+	ErrForbidden       = NewError(KindForbidden, "FORBIDDEN", "not allowed to access this resource")
+
+	ErrAPIKeyNotFound           = NewError(KindNotFound, "API_KEY_NOT_FOUND", "api key not found")
+	ErrAPIKeyNameRequired       = NewError(KindInvalid, "API_KEY_NAME_REQUIRED", "api key name is required")
+	ErrInvalidAPIKeyPermissions = NewError(KindInvalid, "INVALID_API_KEY_PERMISSIONS", "permissions must be named resource:action")
+	ErrInvalidAPIKeyOverlap     = NewError(KindInvalid, "INVALID_API_KEY_OVERLAP", "overlap must be a non-negative duration such as 24h")
+	ErrAPIKeyRevoked            = NewError(KindConflict, "API_KEY_REVOKED", "api key has been revoked")
+	ErrInvalidAPIKey            = NewError(KindUnauthenticated, "INVALID_API_KEY", "api key is invalid, expired or revoked")
+
+	ErrSigningNotConfigured = NewError(KindUnprocessable, "SIGNING_NOT_CONFIGURED", "request signing is not configured on this server")
+	ErrSignatureRequired    = NewError(KindUnauthenticated, "SIGNATURE_REQUIRED", "requests with this api key must be signed")
+	ErrInvalidSignature     = NewError(KindUnauthenticated, "INVALID_SIGNATURE", "request signature is invalid")
+	ErrReplayedRequest      = NewError(KindUnauthenticated, "REPLAYED_REQUEST", "request nonce was already used")
+
+	ErrRateLimited = NewError(KindRateLimited, "RATE_LIMITED", "too many requests, retry later")
+
+	ErrTenantNotFound       = NewError(KindNotFound, "TENANT_NOT_FOUND", "tenant not found")
+	ErrTenantTaken          = NewError(KindConflict, "TENANT_ALREADY_EXISTS", "a tenant with this id or host already exists")
+	ErrInvalidTenantConfig  = NewError(KindInvalid, "INVALID_TENANT_CONFIG", "tenant configuration is invalid")
+	ErrTenantMismatch       = NewError(KindForbidden, "TENANT_MISMATCH", "the host belongs to another tenant")
+	ErrInvestmentNotEnabled = NewError(KindUnprocessable, "INVESTMENT_NOT_ENABLED", "investment is not enabled for this tenant")
+
+	ErrInvalidAuditFilter = NewError(KindInvalid, "INVALID_AUDIT_FILTER", "audit filter is invalid")
+)
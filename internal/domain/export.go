@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type ExportDataset string
+
+const (
+	ExportTransactions ExportDataset = "transactions"
+	ExportHoldings     ExportDataset = "holdings"
+	ExportNABHistory   ExportDataset = "nab_history"
+)
+
+// IsValid reports whether the dataset can be exported
+func (d ExportDataset) IsValid() bool {
+	return d == ExportTransactions || d == ExportHoldings || d == ExportNABHistory
+}
+
+type ExportFormat string
+
+const (
+	ExportCSV     ExportFormat = "csv"
+	ExportJSONL   ExportFormat = "jsonl"
+	ExportParquet ExportFormat = "parquet"
+)
+
+// IsValid reports whether datasets can be exported in the format
+func (f ExportFormat) IsValid() bool {
+	return f == ExportCSV || f == ExportJSONL || f == ExportParquet
+}
+
+// TransactionExport is a transaction row of the transactions dataset
+type TransactionExport struct {
+	ID                string     `json:"id" parquet:"id"`
+	CustomerID        string     `json:"customer_id" parquet:"customer_id"`
+	InvestmentID      string     `json:"investment_id" parquet:"investment_id"`
+	Type              string     `json:"type" parquet:"type"`
+	Status            string     `json:"status" parquet:"status"`
+	Amount            float64    `json:"amount" parquet:"amount"`
+	Fee               float64    `json:"fee" parquet:"fee"`
+	Units             float64    `json:"units" parquet:"units"`
+	NAB               float64    `json:"nab" parquet:"nab"`
+	NABDate           string     `json:"nab_date" parquet:"nab_date"`
+	TransactionDate   time.Time  `json:"transaction_date" parquet:"transaction_date,timestamp"`
+	SettlementDate    *time.Time `json:"settlement_date" parquet:"settlement_date,optional"`
+	CompletedDate     *time.Time `json:"completed_date" parquet:"completed_date,optional"`
+	ReversesID        string     `json:"reverses_id" parquet:"reverses_id"`
+	ExternalReference string     `json:"external_reference" parquet:"external_reference"`
+	UpdatedAt         time.Time  `json:"updated_at" parquet:"updated_at,timestamp"`
+}
+
+// HoldingExport is a customer holding row of the holdings dataset
+type HoldingExport struct {
+	ID           string    `json:"id" parquet:"id"`
+	CustomerID   string    `json:"customer_id" parquet:"customer_id"`
+	InvestmentID string    `json:"investment_id" parquet:"investment_id"`
+	Units        float64   `json:"units" parquet:"units"`
+	UpdatedAt    time.Time `json:"updated_at" parquet:"updated_at,timestamp"`
+}
+
+// NABExport is the NAB an investment was priced at on a day, a row of the
+// nab_history dataset
+type NABExport struct {
+	InvestmentID string    `json:"investment_id" parquet:"investment_id"`
+	Date         string    `json:"date" parquet:"date"` // YYYY-MM-DD
+	NAB          float64   `json:"nab" parquet:"nab"`
+	UpdatedAt    time.Time `json:"updated_at" parquet:"updated_at,timestamp"`
+}
+
+// ExportResult summarizes an export. Watermark is the latest update time of
+// the exported rows, or the requested one when there were none, and is the
+// since of the next incremental export.
+type ExportResult struct {
+	Dataset   ExportDataset `json:"dataset"`
+	Rows      int           `json:"rows"`
+	Watermark time.Time     `json:"watermark"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+type ImportEntity string
+
+const (
+	ImportCustomers   ImportEntity = "customers"
+	ImportInvestments ImportEntity = "investments"
+)
+
+// IsValid reports whether the entity can be bulk imported
+func (e ImportEntity) IsValid() bool {
+	return e == ImportCustomers || e == ImportInvestments
+}
+
+type ImportFormat string
+
+const (
+	ImportCSV   ImportFormat = "csv"   // Header row naming the columns, then one record per row
+	ImportJSONL ImportFormat = "jsonl" // One JSON object per line, shaped like the create request body
+)
+
+// IsValid reports whether files in the format can be imported
+func (f ImportFormat) IsValid() bool {
+	return f == ImportCSV || f == ImportJSONL
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowCreated ImportRowStatus = "CREATED"
+	ImportRowValid   ImportRowStatus = "VALID" // Would be created, reported by dry runs
+	ImportRowFailed  ImportRowStatus = "FAILED"
+)
+
+// ImportRowResult is the outcome of one imported record
+type ImportRowResult struct {
+	Row    int             `json:"row"` // CSV record or JSON lines line number, starting at 1 after any header
+	Status ImportRowStatus `json:"status"`
+	ID     string          `json:"id,omitempty"`
+	Code   string          `json:"code,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// ImportReport summarizes a bulk import
+type ImportReport struct {
+	Entity    ImportEntity       `json:"entity"`
+	DryRun    bool               `json:"dry_run"`
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Rows      []*ImportRowResult `json:"rows"`
+}
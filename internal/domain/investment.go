@@ -1,11 +1,118 @@
 package domain
 
 type Investment struct {
-	ID           string  `json:"id"`
-	Name         string  `json:"name"`
-	TotalUnits   float64 `json:"total_units"`
-	TotalBalance float64 `json:"total_balance"`
-	NAB          float64 `json:"nab"`
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Description   string           `json:"description"`
+	RiskLevel     RiskLevel        `json:"risk_level"`
+	Category      FundCategory     `json:"category"`
+	Currency      string           `json:"currency"`
+	Manager       string           `json:"manager"`
+	InceptionDate string           `json:"inception_date"` // YYYY-MM-DD
+	Status        InvestmentStatus `json:"status"`
+	Rules         InvestmentRules  `json:"rules"`
+	TotalUnits    float64          `json:"total_units"`
+	TotalBalance  float64          `json:"total_balance"`
+	NAB           float64          `json:"nab"`
+}
+
+// UpdateInvestmentRequest holds the editable fields of an investment product.
+// Nil fields are left untouched.
+type UpdateInvestmentRequest struct {
+	Name          *string           `json:"name"`
+	Description   *string           `json:"description"`
+	RiskLevel     *RiskLevel        `json:"risk_level"`
+	Category      *FundCategory     `json:"category"`
+	Currency      *string           `json:"currency"`
+	Manager       *string           `json:"manager"`
+	InceptionDate *string           `json:"inception_date"`
+	Status        *InvestmentStatus `json:"status"`
+	Rules         *InvestmentRules  `json:"rules"`
+}
+
+// InvestmentRules are the amount and holding limits applied to deposits and
+// withdrawals of an investment product. A zero value disables the rule.
+type InvestmentRules struct {
+	MinInitialSubscription    float64 `json:"min_initial_subscription"`
+	MinSubsequentSubscription float64 `json:"min_subsequent_subscription"`
+	MinRedemption             float64 `json:"min_redemption"`
+	MinRemainingBalance       float64 `json:"min_remaining_balance"` // Partial redemptions must leave at least this much
+	MaxHoldingPerCustomer     float64 `json:"max_holding_per_customer"`
+	DailySubscriptionLimit    float64 `json:"daily_subscription_limit"` // Per customer per day
+	DailyRedemptionLimit      float64 `json:"daily_redemption_limit"`   // Per customer per day
+}
+
+// Valid reports whether no rule amount is negative
+func (r InvestmentRules) Valid() bool {
+	return r.MinInitialSubscription >= 0 &&
+		r.MinSubsequentSubscription >= 0 &&
+		r.MinRedemption >= 0 &&
+		r.MinRemainingBalance >= 0 &&
+		r.MaxHoldingPerCustomer >= 0 &&
+		r.DailySubscriptionLimit >= 0 &&
+		r.DailyRedemptionLimit >= 0
+}
+
+type RiskLevel string
+
+const (
+	RiskLow    RiskLevel = "LOW"
+	RiskMedium RiskLevel = "MEDIUM"
+	RiskHigh   RiskLevel = "HIGH"
+)
+
+// Valid reports whether r is a known risk level
+func (r RiskLevel) Valid() bool {
+	return r == RiskLow || r == RiskMedium || r == RiskHigh
+}
+
+type FundCategory string
+
+const (
+	CategoryMoneyMarket FundCategory = "MONEY_MARKET"
+	CategoryBond        FundCategory = "BOND"
+	CategoryEquity      FundCategory = "EQUITY"
+	CategoryMixed       FundCategory = "MIXED"
+)
+
+// Valid reports whether c is a known fund category
+func (c FundCategory) Valid() bool {
+	return c == CategoryMoneyMarket || c == CategoryBond || c == CategoryEquity || c == CategoryMixed
+}
+
+// InvestmentStatus is the lifecycle state of an investment product. OPEN
+// products accept deposits and withdrawals, SUSPENDED products accept
+// neither and CLOSED products only accept withdrawals so remaining holders
+// can redeem.
+type InvestmentStatus string
+
+const (
+	InvestmentOpen      InvestmentStatus = "OPEN"
+	InvestmentSuspended InvestmentStatus = "SUSPENDED"
+	InvestmentClosed    InvestmentStatus = "CLOSED"
+)
+
+// investmentTransitions lists the states reachable from each investment state
+var investmentTransitions = map[InvestmentStatus][]InvestmentStatus{
+	InvestmentOpen:      {InvestmentSuspended, InvestmentClosed},
+	InvestmentSuspended: {InvestmentOpen, InvestmentClosed},
+	InvestmentClosed:    {},
+}
+
+// Valid reports whether s is a known investment state
+func (s InvestmentStatus) Valid() bool {
+	_, ok := investmentTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether an investment in state s may move to next
+func (s InvestmentStatus) CanTransitionTo(next InvestmentStatus) bool {
+	for _, allowed := range investmentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
 }
 
 type CustomerInvestment struct {
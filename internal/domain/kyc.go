@@ -0,0 +1,35 @@
+package domain
+
+// KYCStatus is the know-your-customer verification state of a customer.
+type KYCStatus string
+
+const (
+	KYCUnverified KYCStatus = "UNVERIFIED"
+	KYCPending    KYCStatus = "PENDING"
+	KYCVerified   KYCStatus = "VERIFIED"
+	KYCRejected   KYCStatus = "REJECTED"
+)
+
+// kycTransitions lists the states reachable from each KYC state
+var kycTransitions = map[KYCStatus][]KYCStatus{
+	KYCUnverified: {KYCPending},
+	KYCPending:    {KYCVerified, KYCRejected},
+	KYCRejected:   {KYCPending},
+	KYCVerified:   {},
+}
+
+// Valid reports whether s is a known KYC state
+func (s KYCStatus) Valid() bool {
+	_, ok := kycTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a customer in state s may move to next
+func (s KYCStatus) CanTransitionTo(next KYCStatus) bool {
+	for _, allowed := range kycTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
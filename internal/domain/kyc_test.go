@@ -0,0 +1,35 @@
+package domain
+
+import "testing"
+
+func TestKYCStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from KYCStatus
+		to   KYCStatus
+		want bool
+	}{
+		{KYCUnverified, KYCPending, true},
+		{KYCUnverified, KYCVerified, false},
+		{KYCPending, KYCVerified, true},
+		{KYCPending, KYCRejected, true},
+		{KYCRejected, KYCPending, true},
+		{KYCRejected, KYCVerified, false},
+		{KYCVerified, KYCPending, false},
+		{KYCVerified, KYCRejected, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestKYCStatusValid(t *testing.T) {
+	if !KYCPending.Valid() {
+		t.Error("PENDING should be a valid kyc status")
+	}
+	if KYCStatus("APPROVED").Valid() {
+		t.Error("APPROVED should not be a valid kyc status")
+	}
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+type AccountType string
+
+const (
+	AccountCustomerCash        AccountType = "CUSTOMER_CASH"         // Customer wallet cash
+	AccountCustomerPendingCash AccountType = "CUSTOMER_PENDING_CASH" // Redemption proceeds awaiting settlement
+	AccountCustomerUnits       AccountType = "CUSTOMER_UNITS"        // Units a customer holds in a fund
+	AccountFundCash            AccountType = "FUND_CASH"             // Cash invested in a fund
+	AccountFundUnits           AccountType = "FUND_UNITS"            // Units issued by a fund, negative while outstanding
+	AccountFees                AccountType = "FEES"                  // Fees collected on a fund
+	AccountExternal            AccountType = "EXTERNAL"              // Counterparty outside the platform, such as banks and seed capital
+)
+
+// Asset is what a journal line moves. Entries must balance per asset.
+type Asset string
+
+const (
+	AssetCash  Asset = "CASH"
+	AssetUnits Asset = "UNITS"
+)
+
+type JournalEntryType string
+
+const (
+	EntryTopUp          JournalEntryType = "TOP_UP"
+	EntryPayout         JournalEntryType = "PAYOUT"
+	EntrySubscription   JournalEntryType = "SUBSCRIPTION"
+	EntryRedemption     JournalEntryType = "REDEMPTION"
+	EntrySettlement     JournalEntryType = "SETTLEMENT"
+	EntryReversal       JournalEntryType = "REVERSAL"
+	EntryOpeningBalance JournalEntryType = "OPENING_BALANCE"
+)
+
+// BalanceTolerance absorbs floating point noise when comparing balances,
+// it is below the precision of both cash and units
+const BalanceTolerance = 0.00005
+
+// Account identifies a ledger account. Customer and fund accounts are kept
+// per customer and per investment.
+type Account struct {
+	Type         AccountType `json:"type"`
+	CustomerID   string      `json:"customer_id,omitempty"`
+	InvestmentID string      `json:"investment_id,omitempty"`
+}
+
+func CustomerCashAccount(customerID string) Account {
+	return Account{Type: AccountCustomerCash, CustomerID: customerID}
+}
+
+func CustomerPendingCashAccount(customerID string) Account {
+	return Account{Type: AccountCustomerPendingCash, CustomerID: customerID}
+}
+
+func CustomerUnitsAccount(customerID, investmentID string) Account {
+	return Account{Type: AccountCustomerUnits, CustomerID: customerID, InvestmentID: investmentID}
+}
+
+func FundCashAccount(investmentID string) Account {
+	return Account{Type: AccountFundCash, InvestmentID: investmentID}
+}
+
+func FundUnitsAccount(investmentID string) Account {
+	return Account{Type: AccountFundUnits, InvestmentID: investmentID}
+}
+
+func FeesAccount(investmentID string) Account {
+	return Account{Type: AccountFees, InvestmentID: investmentID}
+}
+
+func ExternalAccount() Account {
+	return Account{Type: AccountExternal}
+}
+
+// JournalLine changes the balance of one account. A positive amount
+// increases what the account holds, a negative amount decreases it.
+type JournalLine struct {
+	Account
+	Asset  Asset   `json:"asset"`
+	Amount float64 `json:"amount"`
+}
+
+type JournalEntry struct {
+	ID            string           `json:"id"`
+	Type          JournalEntryType `json:"type"`
+	TransactionID string           `json:"transaction_id,omitempty"`
+	Reference     string           `json:"reference,omitempty"`
+	Lines         []JournalLine    `json:"lines"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+// Transfer moves amount of asset from one account to another. Zero
+// amounts are left out of the entry.
+func (e *JournalEntry) Transfer(asset Asset, from, to Account, amount float64) {
+	if amount == 0 {
+		return
+	}
+	e.Lines = append(e.Lines,
+		JournalLine{Account: from, Asset: asset, Amount: -amount},
+		JournalLine{Account: to, Asset: asset, Amount: amount})
+}
+
+// Validate checks the entry has lines and balances for every asset
+func (e *JournalEntry) Validate() error {
+	if len(e.Lines) < 2 {
+		return ErrUnbalancedJournalEntry
+	}
+
+	totals := map[Asset]float64{}
+	for _, line := range e.Lines {
+		totals[line.Asset] += line.Amount
+	}
+	for _, total := range totals {
+		if math.Abs(total) > BalanceTolerance {
+			return ErrUnbalancedJournalEntry
+		}
+	}
+	return nil
+}
+
+// AccountBalance is the journal balance of an account for one asset
+type AccountBalance struct {
+	Account
+	Asset   Asset   `json:"asset"`
+	Balance float64 `json:"balance"`
+}
+
+// LedgerDiscrepancy is a stored running total that disagrees with the journal
+type LedgerDiscrepancy struct {
+	Account
+	Asset         Asset   `json:"asset"`
+	LedgerBalance float64 `json:"ledger_balance"`
+	StoredBalance float64 `json:"stored_balance"`
+	Difference    float64 `json:"difference"`
+}
+
+// NewLedgerDiscrepancy returns the discrepancy between the journal and
+// stored balances, or nil when they agree
+func NewLedgerDiscrepancy(account Account, asset Asset, ledgerBalance, storedBalance float64) *LedgerDiscrepancy {
+	difference := storedBalance - ledgerBalance
+	if math.Abs(difference) <= BalanceTolerance {
+		return nil
+	}
+	return &LedgerDiscrepancy{
+		Account:       account,
+		Asset:         asset,
+		LedgerBalance: ledgerBalance,
+		StoredBalance: storedBalance,
+		Difference:    difference,
+	}
+}
+
+type LedgerVerification struct {
+	Balanced      bool                 `json:"balanced"`
+	Discrepancies []*LedgerDiscrepancy `json:"discrepancies"`
+	VerifiedAt    time.Time            `json:"verified_at"`
+}
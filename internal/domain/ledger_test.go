@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJournalEntryValidate(t *testing.T) {
+	customerCash := CustomerCashAccount("c1")
+	fundCash := FundCashAccount("i1")
+
+	tests := []struct {
+		name  string
+		entry func() *JournalEntry
+		want  error
+	}{
+		{"Empty entry", func() *JournalEntry {
+			return &JournalEntry{Type: EntrySubscription}
+		}, ErrUnbalancedJournalEntry},
+		{"Balanced transfers", func() *JournalEntry {
+			entry := &JournalEntry{Type: EntrySubscription}
+			entry.Transfer(AssetCash, customerCash, fundCash, 99000)
+			entry.Transfer(AssetCash, customerCash, FeesAccount("i1"), 1000)
+			entry.Transfer(AssetUnits, FundUnitsAccount("i1"), CustomerUnitsAccount("c1", "i1"), 99)
+			return entry
+		}, nil},
+		{"Unbalanced cash", func() *JournalEntry {
+			return &JournalEntry{Type: EntryTopUp, Lines: []JournalLine{
+				{Account: customerCash, Asset: AssetCash, Amount: 100},
+				{Account: ExternalAccount(), Asset: AssetCash, Amount: -90},
+			}}
+		}, ErrUnbalancedJournalEntry},
+		{"Cash cannot balance units", func() *JournalEntry {
+			return &JournalEntry{Type: EntrySubscription, Lines: []JournalLine{
+				{Account: customerCash, Asset: AssetCash, Amount: -100},
+				{Account: CustomerUnitsAccount("c1", "i1"), Asset: AssetUnits, Amount: 100},
+			}}
+		}, ErrUnbalancedJournalEntry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.entry().Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestJournalEntryTransferSkipsZero(t *testing.T) {
+	entry := &JournalEntry{Type: EntryRedemption}
+	entry.Transfer(AssetCash, FundCashAccount("i1"), FeesAccount("i1"), 0)
+
+	if len(entry.Lines) != 0 {
+		t.Errorf("Transfer() added %d lines for a zero amount", len(entry.Lines))
+	}
+}
+
+func TestNewLedgerDiscrepancy(t *testing.T) {
+	account := FundCashAccount("i1")
+
+	if d := NewLedgerDiscrepancy(account, AssetCash, 100.10, 100.10); d != nil {
+		t.Errorf("NewLedgerDiscrepancy() = %+v, want nil", d)
+	}
+
+	d := NewLedgerDiscrepancy(account, AssetCash, 100, 150)
+	if d == nil || d.Difference != 50 {
+		t.Errorf("NewLedgerDiscrepancy() = %+v, want difference 50", d)
+	}
+}
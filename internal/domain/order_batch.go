@@ -0,0 +1,40 @@
+package domain
+
+// BatchOrder is one order of a partner's batch order file
+type BatchOrder struct {
+	CustomerID        string  `json:"customer_id"`
+	InvestmentID      string  `json:"investment_id"`
+	Type              string  `json:"type"` // DEPOSIT or WITHDRAW
+	Amount            float64 `json:"amount"`
+	Units             float64 `json:"units"` // Withdrawals only, redeemed instead of Amount
+	ExternalReference string  `json:"external_reference"`
+	RiskAcknowledged  bool    `json:"risk_acknowledged"`
+}
+
+type OrderResultStatus string
+
+const (
+	OrderProcessed OrderResultStatus = "PROCESSED"
+	OrderDuplicate OrderResultStatus = "DUPLICATE" // Reference already processed, TransactionID is the earlier transaction
+	OrderFailed    OrderResultStatus = "FAILED"
+)
+
+// OrderResult is the outcome of one order of a batch
+type OrderResult struct {
+	Row               int               `json:"row"` // CSV record number, starting at 1 after the header
+	ExternalReference string            `json:"external_reference"`
+	Status            OrderResultStatus `json:"status"`
+	TransactionID     string            `json:"transaction_id,omitempty"`
+	TransactionStatus string            `json:"transaction_status,omitempty"`
+	Code              string            `json:"code,omitempty"`
+	Error             string            `json:"error,omitempty"`
+}
+
+// OrderBatchReport summarizes a processed batch order file
+type OrderBatchReport struct {
+	Total      int            `json:"total"`
+	Processed  int            `json:"processed"`
+	Duplicates int            `json:"duplicates"`
+	Failed     int            `json:"failed"`
+	Results    []*OrderResult `json:"results"`
+}
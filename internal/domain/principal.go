@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+// Roles known to the default access policy
+const (
+	RoleCustomer      = "customer"
+	RoleOperator      = "operator"
+	RoleFundAdmin     = "fund-admin"
+	RoleAuditor       = "auditor"
+	RolePlatformAdmin = "platform-admin" // Manages the tenants themselves
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject           string   `json:"subject"`
+	TenantID          string   `json:"tenant_id,omitempty"`   // Set when the caller belongs to one tenant
+	CustomerID        string   `json:"customer_id,omitempty"` // Set for customers, who may only act on their own ID
+	Roles             []string `json:"roles"`
+	APIKeyID          string   `json:"api_key_id,omitempty"`         // Set for partners authenticated with an API key
+	Permissions       []string `json:"permissions,omitempty"`        // Granted directly rather than through roles
+	CustomerIDs       []string `json:"customer_ids,omitempty"`       // Customers an API key is scoped to, empty for all
+	SignatureRequired bool     `json:"signature_required,omitempty"` // API key transaction requests must be signed
+}
+
+// IsCustomer reports whether the principal acts on behalf of a single customer
+func (p *Principal) IsCustomer() bool {
+	return p.CustomerID != ""
+}
+
+// HasCustomerScope reports whether the principal is limited to some customers
+func (p *Principal) HasCustomerScope() bool {
+	return p.IsCustomer() || len(p.CustomerIDs) > 0
+}
+
+// CanAccessCustomer reports whether the principal may read or transact on
+// the customer's data
+func (p *Principal) CanAccessCustomer(customerID string) bool {
+	if p.IsCustomer() {
+		return p.CustomerID == customerID
+	}
+	return len(p.CustomerIDs) == 0 || slices.Contains(p.CustomerIDs, customerID)
+}
+
+// HomeTenant returns the tenant the principal is confined to: its own, or
+// the default tenant for principals issued without one. Only platform admins
+// without a tenant may act in any tenant, and get an empty string.
+func (p *Principal) HomeTenant() string {
+	if p.TenantID == "" && !slices.Contains(p.Roles, RolePlatformAdmin) {
+		return DefaultTenantID
+	}
+	return p.TenantID
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of the request, if it was
+// authenticated
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// TransactionPosition is the net effect of a customer's transactions on
+// one investment
+type TransactionPosition struct {
+	CustomerID   string  `json:"customer_id"`
+	InvestmentID string  `json:"investment_id"`
+	Units        float64 `json:"units"`    // Units deposited minus units withdrawn
+	NetCash      float64 `json:"net_cash"` // Cash invested in the fund after fees minus cash withdrawn
+}
+
+// InvestmentDiscrepancy is a fund whose stored totals differ from the totals
+// recomputed from its opening balance and transaction history
+type InvestmentDiscrepancy struct {
+	InvestmentID    string  `json:"investment_id"`
+	Name            string  `json:"name"`
+	StoredUnits     float64 `json:"stored_units"`
+	ExpectedUnits   float64 `json:"expected_units"`
+	StoredBalance   float64 `json:"stored_balance"`
+	ExpectedBalance float64 `json:"expected_balance"`
+}
+
+// HoldingDiscrepancy is a customer holding whose units differ from the units
+// recomputed from the customer's transaction history
+type HoldingDiscrepancy struct {
+	CustomerID    string  `json:"customer_id"`
+	InvestmentID  string  `json:"investment_id"`
+	StoredUnits   float64 `json:"stored_units"`
+	ExpectedUnits float64 `json:"expected_units"`
+}
+
+type ReconciliationReport struct {
+	Balanced           bool                     `json:"balanced"`
+	Repaired           bool                     `json:"repaired"`
+	CheckedInvestments int                      `json:"checked_investments"`
+	CheckedHoldings    int                      `json:"checked_holdings"`
+	Investments        []*InvestmentDiscrepancy `json:"investments"`
+	Holdings           []*HoldingDiscrepancy    `json:"holdings"`
+	ReconciledAt       time.Time                `json:"reconciled_at"`
+}
@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+type PlanFrequency string
+
+const (
+	FrequencyDaily   PlanFrequency = "DAILY"
+	FrequencyWeekly  PlanFrequency = "WEEKLY"
+	FrequencyMonthly PlanFrequency = "MONTHLY"
+)
+
+// Valid reports whether f is a known plan frequency
+func (f PlanFrequency) Valid() bool {
+	return f == FrequencyDaily || f == FrequencyWeekly || f == FrequencyMonthly
+}
+
+type PlanStatus string
+
+const (
+	PlanActive    PlanStatus = "ACTIVE"
+	PlanPaused    PlanStatus = "PAUSED"
+	PlanCompleted PlanStatus = "COMPLETED"
+	PlanCancelled PlanStatus = "CANCELLED"
+)
+
+// RecurringPlan deposits Amount into an investment on every scheduled date
+// between StartDate and the optional EndDate. Dates are formatted YYYY-MM-DD.
+type RecurringPlan struct {
+	ID               string        `json:"id"`
+	CustomerID       string        `json:"customer_id"`
+	InvestmentID     string        `json:"investment_id"`
+	Amount           float64       `json:"amount"`
+	Frequency        PlanFrequency `json:"frequency"`
+	StartDate        string        `json:"start_date"`
+	EndDate          string        `json:"end_date,omitempty"`
+	NextRunDate      string        `json:"next_run_date,omitempty"`
+	Status           PlanStatus    `json:"status"`
+	RiskAcknowledged bool          `json:"risk_acknowledged"`
+	Attempts         int           `json:"attempts"`              // Failed attempts for the current run
+	RetryAt          *time.Time    `json:"retry_at,omitempty"`    // When the current run is retried
+	LastRunAt        *time.Time    `json:"last_run_at,omitempty"` // When a deposit was last attempted
+	LastError        string        `json:"last_error,omitempty"`  // Error of the last failed attempt
+	LockedUntil      *time.Time    `json:"-"`                     // Until when a worker has claimed the plan
+	CreatedAt        time.Time     `json:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at"`
+}
+
+// IsDue reports whether an active plan should run at now: its scheduled date
+// has come and any retry is due
+func (p *RecurringPlan) IsDue(now time.Time) bool {
+	return p.Status == PlanActive &&
+		p.NextRunDate != "" && p.NextRunDate <= now.Format(time.DateOnly) &&
+		(p.RetryAt == nil || !p.RetryAt.After(now))
+}
+
+type CreateRecurringPlanRequest struct {
+	CustomerID       string        `json:"customer_id"`
+	InvestmentID     string        `json:"investment_id"`
+	Amount           float64       `json:"amount"`
+	Frequency        PlanFrequency `json:"frequency"`
+	StartDate        string        `json:"start_date"`
+	EndDate          string        `json:"end_date"`
+	RiskAcknowledged bool          `json:"risk_acknowledged"`
+}
+
+// UpdateRecurringPlanRequest holds the editable fields of a plan. Nil fields
+// are left untouched, an empty end date removes it.
+type UpdateRecurringPlanRequest struct {
+	Amount    *float64       `json:"amount"`
+	Frequency *PlanFrequency `json:"frequency"`
+	EndDate   *string        `json:"end_date"`
+}
+
+type PlanRunStatus string
+
+const (
+	PlanRunSucceeded PlanRunStatus = "SUCCEEDED"
+	PlanRunFailed    PlanRunStatus = "FAILED"
+	PlanRunSkipped   PlanRunStatus = "SKIPPED"
+)
+
+// RecurringPlanRun is the outcome of one scheduled date of a plan
+type RecurringPlanRun struct {
+	ID            string        `json:"id"`
+	PlanID        string        `json:"plan_id"`
+	ScheduledFor  string        `json:"scheduled_for"`
+	Status        PlanRunStatus `json:"status"`
+	Attempts      int           `json:"attempts"`
+	TransactionID string        `json:"transaction_id,omitempty"`
+	Error         string        `json:"error,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// NextScheduledDate returns the scheduled date following current for a plan
+// starting on start. Monthly plans keep the day of month of start, clamped to
+// the length of shorter months.
+func NextScheduledDate(frequency PlanFrequency, start, current time.Time) time.Time {
+	switch frequency {
+	case FrequencyDaily:
+		return current.AddDate(0, 0, 1)
+	case FrequencyWeekly:
+		return current.AddDate(0, 0, 7)
+	default:
+		year, month, _ := current.Date()
+		firstOfNext := time.Date(year, month+1, 1, 0, 0, 0, 0, current.Location())
+		lastDay := firstOfNext.AddDate(0, 1, -1).Day()
+		day := min(start.Day(), lastDay)
+		return time.Date(firstOfNext.Year(), firstOfNext.Month(), day, 0, 0, 0, 0, current.Location())
+	}
+}
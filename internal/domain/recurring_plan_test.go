@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextScheduledDate(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		frequency PlanFrequency
+		start     string
+		current   string
+		want      string
+	}{
+		{"Daily", FrequencyDaily, "2026-01-01", "2026-01-31", "2026-02-01"},
+		{"Weekly", FrequencyWeekly, "2026-01-01", "2026-01-29", "2026-02-05"},
+		{"Monthly", FrequencyMonthly, "2026-01-15", "2026-01-15", "2026-02-15"},
+		{"Monthly clamps to short month", FrequencyMonthly, "2026-01-31", "2026-01-31", "2026-02-28"},
+		{"Monthly restores day after short month", FrequencyMonthly, "2026-01-31", "2026-02-28", "2026-03-31"},
+		{"Monthly across year end", FrequencyMonthly, "2026-01-10", "2026-12-10", "2027-01-10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextScheduledDate(tt.frequency, date(tt.start), date(tt.current))
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("NextScheduledDate() = %s, want %s", got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
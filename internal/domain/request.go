@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+// RequestInfo identifies the HTTP request work is done for
+type RequestInfo struct {
+	ID string // From the X-Request-ID header, or generated
+	IP string // Address of the client
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the request info
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request ctx belongs to, empty outside
+// a request
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
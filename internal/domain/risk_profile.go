@@ -0,0 +1,184 @@
+package domain
+
+import "time"
+
+// RiskProfile is the customer's tolerance for investment risk, derived from
+// the risk questionnaire.
+type RiskProfile string
+
+const (
+	RiskProfileConservative RiskProfile = "CONSERVATIVE"
+	RiskProfileModerate     RiskProfile = "MODERATE"
+	RiskProfileAggressive   RiskProfile = "AGGRESSIVE"
+)
+
+// MaxRiskLevel returns the highest investment risk level suitable for the profile
+func (p RiskProfile) MaxRiskLevel() RiskLevel {
+	switch p {
+	case RiskProfileAggressive:
+		return RiskHigh
+	case RiskProfileModerate:
+		return RiskMedium
+	default:
+		return RiskLow
+	}
+}
+
+// Rank orders risk levels from LOW (1) to HIGH (3)
+func (r RiskLevel) Rank() int {
+	switch r {
+	case RiskLow:
+		return 1
+	case RiskMedium:
+		return 2
+	case RiskHigh:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Suits reports whether an investment with the given risk level is suitable
+// for a customer with profile p
+func (p RiskProfile) Suits(level RiskLevel) bool {
+	return level.Rank() <= p.MaxRiskLevel().Rank()
+}
+
+type RiskQuestion struct {
+	ID      string       `json:"id"`
+	Text    string       `json:"text"`
+	Options []RiskOption `json:"options"`
+}
+
+type RiskOption struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Score int    `json:"score"`
+}
+
+type RiskAnswer struct {
+	QuestionID string `json:"question_id"`
+	OptionID   string `json:"option_id"`
+}
+
+type RiskProfileRequest struct {
+	Answers []RiskAnswer `json:"answers"`
+}
+
+type CustomerRiskProfile struct {
+	ID         string       `json:"id"`
+	CustomerID string       `json:"customer_id"`
+	Profile    RiskProfile  `json:"profile"`
+	Score      int          `json:"score"`
+	Answers    []RiskAnswer `json:"answers"`
+	AssessedAt time.Time    `json:"assessed_at"`
+	ExpiresAt  time.Time    `json:"expires_at"`
+}
+
+// Expired reports whether the profile is no longer valid at t
+func (p *CustomerRiskProfile) Expired(t time.Time) bool {
+	return !t.Before(p.ExpiresAt)
+}
+
+// RiskQuestionnaire is the questionnaire customers answer to obtain a risk profile
+var RiskQuestionnaire = []RiskQuestion{
+	{
+		ID:   "horizon",
+		Text: "How long do you plan to keep this money invested?",
+		Options: []RiskOption{
+			{ID: "lt1y", Text: "Less than 1 year", Score: 1},
+			{ID: "1to3y", Text: "1 to 3 years", Score: 2},
+			{ID: "3to5y", Text: "3 to 5 years", Score: 3},
+			{ID: "gt5y", Text: "More than 5 years", Score: 4},
+		},
+	},
+	{
+		ID:   "objective",
+		Text: "What is your main investment objective?",
+		Options: []RiskOption{
+			{ID: "preserve", Text: "Preserve my capital", Score: 1},
+			{ID: "income", Text: "Regular income", Score: 2},
+			{ID: "balanced", Text: "Balance of income and growth", Score: 3},
+			{ID: "growth", Text: "Long term growth", Score: 4},
+		},
+	},
+	{
+		ID:   "drawdown",
+		Text: "Your portfolio drops 20% in a month. What do you do?",
+		Options: []RiskOption{
+			{ID: "sell_all", Text: "Sell everything", Score: 1},
+			{ID: "sell_some", Text: "Sell some of it", Score: 2},
+			{ID: "hold", Text: "Hold and wait", Score: 3},
+			{ID: "buy_more", Text: "Buy more", Score: 4},
+		},
+	},
+	{
+		ID:   "experience",
+		Text: "How much investment experience do you have?",
+		Options: []RiskOption{
+			{ID: "none", Text: "None, savings accounts only", Score: 1},
+			{ID: "fixed_income", Text: "Deposits, bonds or money market funds", Score: 2},
+			{ID: "mixed", Text: "Mixed or balanced funds", Score: 3},
+			{ID: "equity", Text: "Stocks or equity funds", Score: 4},
+		},
+	},
+	{
+		ID:   "income_share",
+		Text: "What share of your monthly income can you invest without affecting daily needs?",
+		Options: []RiskOption{
+			{ID: "lt10", Text: "Less than 10%", Score: 1},
+			{ID: "10to25", Text: "10% to 25%", Score: 2},
+			{ID: "25to50", Text: "25% to 50%", Score: 3},
+			{ID: "gt50", Text: "More than 50%", Score: 4},
+		},
+	},
+}
+
+// ScoreRiskAnswers scores a complete set of answers to RiskQuestionnaire and
+// returns the total score with the resulting profile. Every question must be
+// answered exactly once.
+func ScoreRiskAnswers(answers []RiskAnswer) (int, RiskProfile, error) {
+	if len(answers) != len(RiskQuestionnaire) {
+		return 0, "", ErrIncompleteRiskAnswers
+	}
+
+	chosen := make(map[string]string, len(answers))
+	for _, answer := range answers {
+		if _, duplicate := chosen[answer.QuestionID]; duplicate {
+			return 0, "", ErrInvalidRiskAnswer
+		}
+		chosen[answer.QuestionID] = answer.OptionID
+	}
+
+	score, maxScore := 0, 0
+	for _, question := range RiskQuestionnaire {
+		optionID, ok := chosen[question.ID]
+		if !ok {
+			return 0, "", ErrIncompleteRiskAnswers
+		}
+
+		found := false
+		questionMax := 0
+		for _, option := range question.Options {
+			if option.ID == optionID {
+				score += option.Score
+				found = true
+			}
+			questionMax = max(questionMax, option.Score)
+		}
+		if !found {
+			return 0, "", ErrInvalidRiskAnswer
+		}
+		maxScore += questionMax
+	}
+
+	// Up to 40% of the maximum score is conservative, up to 70% moderate
+	switch {
+	case score*10 <= maxScore*4:
+		return score, RiskProfileConservative, nil
+	case score*10 <= maxScore*7:
+		return score, RiskProfileModerate, nil
+	default:
+		return score, RiskProfileAggressive, nil
+	}
+}
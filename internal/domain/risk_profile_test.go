@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+// answersWithScore answers every question with the option scoring score
+func answersWithScore(score int) []RiskAnswer {
+	answers := []RiskAnswer{}
+	for _, question := range RiskQuestionnaire {
+		for _, option := range question.Options {
+			if option.Score == score {
+				answers = append(answers, RiskAnswer{QuestionID: question.ID, OptionID: option.ID})
+			}
+		}
+	}
+	return answers
+}
+
+func TestScoreRiskAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []RiskAnswer
+		score   int
+		profile RiskProfile
+	}{
+		{"Lowest answers", answersWithScore(1), 5, RiskProfileConservative},
+		{"Second answers", answersWithScore(2), 10, RiskProfileModerate},
+		{"Third answers", answersWithScore(3), 15, RiskProfileAggressive},
+		{"Highest answers", answersWithScore(4), 20, RiskProfileAggressive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, profile, err := ScoreRiskAnswers(tt.answers)
+			if err != nil {
+				t.Fatalf("ScoreRiskAnswers returned error: %v", err)
+			}
+			if score != tt.score || profile != tt.profile {
+				t.Errorf("ScoreRiskAnswers = (%d, %s), want (%d, %s)", score, profile, tt.score, tt.profile)
+			}
+		})
+	}
+}
+
+func TestScoreRiskAnswersInvalid(t *testing.T) {
+	missing := answersWithScore(1)[1:]
+	if _, _, err := ScoreRiskAnswers(missing); !errors.Is(err, ErrIncompleteRiskAnswers) {
+		t.Errorf("missing answer: got %v, want %v", err, ErrIncompleteRiskAnswers)
+	}
+
+	duplicate := answersWithScore(1)
+	duplicate[1] = duplicate[0]
+	if _, _, err := ScoreRiskAnswers(duplicate); !errors.Is(err, ErrInvalidRiskAnswer) {
+		t.Errorf("duplicate answer: got %v, want %v", err, ErrInvalidRiskAnswer)
+	}
+
+	unknown := answersWithScore(1)
+	unknown[0].OptionID = "unknown"
+	if _, _, err := ScoreRiskAnswers(unknown); !errors.Is(err, ErrInvalidRiskAnswer) {
+		t.Errorf("unknown option: got %v, want %v", err, ErrInvalidRiskAnswer)
+	}
+}
+
+func TestRiskProfileSuits(t *testing.T) {
+	if !RiskProfileModerate.Suits(RiskMedium) {
+		t.Error("moderate profile should suit medium risk")
+	}
+	if RiskProfileModerate.Suits(RiskHigh) {
+		t.Error("moderate profile should not suit high risk")
+	}
+	if !RiskProfileAggressive.Suits(RiskHigh) {
+		t.Error("aggressive profile should suit high risk")
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+type StatementFormat string
+
+const (
+	StatementCSV StatementFormat = "csv"
+	StatementPDF StatementFormat = "pdf"
+)
+
+// IsValid reports whether the format is one statements can be exported as
+func (f StatementFormat) IsValid() bool {
+	return f == StatementCSV || f == StatementPDF
+}
+
+// StatementHolding is a customer's position in one investment at the start
+// or end of a statement period
+type StatementHolding struct {
+	InvestmentID   string  `json:"investment_id"`
+	InvestmentName string  `json:"investment_name"`
+	Units          float64 `json:"units"`
+	NAB            float64 `json:"nab"`
+	NABDate        string  `json:"nab_date,omitempty"` // Date of the NAB the holding is valued at
+	Value          float64 `json:"value"`
+}
+
+// RealizedGain is the gain or loss a withdrawal realized against the average
+// cost of the units it redeemed. Reversing the withdrawal realizes the
+// opposite amount.
+type RealizedGain struct {
+	TransactionID  string    `json:"transaction_id"`
+	InvestmentID   string    `json:"investment_id"`
+	InvestmentName string    `json:"investment_name"`
+	Date           time.Time `json:"date"`
+	Units          float64   `json:"units"`
+	Proceeds       float64   `json:"proceeds"`   // Withdrawal amount after fees
+	CostBasis      float64   `json:"cost_basis"` // Average cost of the redeemed units, fees included
+	Gain           float64   `json:"gain"`
+}
+
+// Statement is a customer's account statement for a period of calendar days
+type Statement struct {
+	CustomerID        string              `json:"customer_id"`
+	CustomerName      string              `json:"customer_name"`
+	CustomerEmail     string              `json:"customer_email"`
+	From              string              `json:"from"` // YYYY-MM-DD, inclusive
+	To                string              `json:"to"`   // YYYY-MM-DD, inclusive
+	OpeningHoldings   []*StatementHolding `json:"opening_holdings"`
+	Transactions      []*Transaction      `json:"transactions"`
+	ClosingHoldings   []*StatementHolding `json:"closing_holdings"`
+	RealizedGains     []*RealizedGain     `json:"realized_gains"`
+	OpeningValue      float64             `json:"opening_value"`
+	ClosingValue      float64             `json:"closing_value"`
+	TotalRealizedGain float64             `json:"total_realized_gain"`
+	GeneratedAt       time.Time           `json:"generated_at"`
+}
@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTenantID is the tenant of requests no other tenant is resolved for,
+// and of every record created before tenants existed
+const DefaultTenantID = "default"
+
+// CutOffLayout is the layout of tenant cut-off times
+const CutOffLayout = "15:04"
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,35}$`)
+
+// Tenant is a distributor the product runs for under its own brand. Its
+// customers, investments and transactions are invisible to other tenants.
+type Tenant struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Host      string       `json:"host,omitempty"` // Requests to this host belong to the tenant
+	Config    TenantConfig `json:"config"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TenantConfig holds the business rules a tenant sets for its orders
+type TenantConfig struct {
+	SubscriptionFeeRate  *float64 `json:"subscription_fee_rate"`  // Replaces the platform rate, when set
+	RedemptionFeeRate    *float64 `json:"redemption_fee_rate"`    // Replaces the platform rate, when set
+	CutOffTime           string   `json:"cut_off_time"`           // HH:MM, orders placed later trade on the next day
+	EnabledInvestmentIDs []string `json:"enabled_investment_ids"` // Investments open to deposits, empty for all
+}
+
+// Validate checks fee rates are fractions below 1, the cut-off is a time of
+// day and investment IDs are not empty
+func (c *TenantConfig) Validate() error {
+	for _, rate := range []*float64{c.SubscriptionFeeRate, c.RedemptionFeeRate} {
+		if rate != nil && (*rate < 0 || *rate >= 1) {
+			return ErrInvalidTenantConfig.WithMessage("fee rates must be at least 0 and below 1")
+		}
+	}
+	if c.CutOffTime != "" {
+		if _, err := time.Parse(CutOffLayout, c.CutOffTime); err != nil {
+			return ErrInvalidTenantConfig.WithMessage("cut-off time must be formatted as HH:MM")
+		}
+	}
+	if slices.Contains(c.EnabledInvestmentIDs, "") {
+		return ErrInvalidTenantConfig.WithMessage("enabled investment IDs cannot be empty")
+	}
+	return nil
+}
+
+// EnablesInvestment reports whether the tenant accepts deposits into the
+// investment
+func (c *TenantConfig) EnablesInvestment(id string) bool {
+	return len(c.EnabledInvestmentIDs) == 0 || slices.Contains(c.EnabledInvestmentIDs, id)
+}
+
+// TradeDate returns the day an order placed at now trades on: the same day
+// before the cut-off time and the next day from it on
+func (c *TenantConfig) TradeDate(now time.Time) time.Time {
+	cutOff, err := time.Parse(CutOffLayout, c.CutOffTime)
+	if err != nil {
+		return now
+	}
+
+	if now.Hour()*60+now.Minute() >= cutOff.Hour()*60+cutOff.Minute() {
+		return now.AddDate(0, 0, 1)
+	}
+	return now
+}
+
+// FeeRates returns the subscription and redemption fee rates of the tenant,
+// falling back to the platform rates it does not replace
+func (c *TenantConfig) FeeRates(subscription, redemption float64) (float64, float64) {
+	if c.SubscriptionFeeRate != nil {
+		subscription = *c.SubscriptionFeeRate
+	}
+	if c.RedemptionFeeRate != nil {
+		redemption = *c.RedemptionFeeRate
+	}
+	return subscription, redemption
+}
+
+type CreateTenantRequest struct {
+	ID     string       `json:"id"`
+	Name   string       `json:"name"`
+	Host   string       `json:"host"`
+	Config TenantConfig `json:"config"`
+}
+
+// Validate checks the ID is a lowercase slug and the name and config are set
+// correctly
+func (r *CreateTenantRequest) Validate() error {
+	if !tenantIDPattern.MatchString(r.ID) {
+		return ErrInvalidTenantConfig.WithMessage("id must be up to 36 lowercase letters, digits and dashes")
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrInvalidTenantConfig.WithMessage("name is required")
+	}
+	return r.Config.Validate()
+}
+
+// UpdateTenantRequest holds the editable fields of a tenant. Nil fields are
+// left untouched, a config replaces the whole config.
+type UpdateTenantRequest struct {
+	Name   *string       `json:"name"`
+	Host   *string       `json:"host"`
+	Config *TenantConfig `json:"config"`
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx scoped to the tenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to, the default tenant
+// when none was set
+func TenantFromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(tenantKey{}).(string); ok {
+		return tenantID
+	}
+	return DefaultTenantID
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTenantConfigTradeDate(t *testing.T) {
+	day := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name   string
+		cutOff string
+		at     time.Duration
+		want   time.Time
+	}{
+		{"no cut-off", "", 23 * time.Hour, day},
+		{"before cut-off", "15:00", 14*time.Hour + 59*time.Minute, day},
+		{"at cut-off", "15:00", 15 * time.Hour, day.AddDate(0, 0, 1)},
+		{"after cut-off", "15:00", 20 * time.Hour, day.AddDate(0, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := TenantConfig{CutOffTime: tt.cutOff}
+			if got := config.TradeDate(day.Add(tt.at)); got.Format(time.DateOnly) != tt.want.Format(time.DateOnly) {
+				t.Errorf("TradeDate() = %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestTenantConfigFeeRates(t *testing.T) {
+	rate := 0.02
+	config := TenantConfig{RedemptionFeeRate: &rate}
+
+	subscription, redemption := config.FeeRates(0.01, 0.005)
+	if subscription != 0.01 || redemption != 0.02 {
+		t.Errorf("FeeRates() = %v, %v, want 0.01, 0.02", subscription, redemption)
+	}
+}
+
+func TestTenantConfigEnablesInvestment(t *testing.T) {
+	all := TenantConfig{}
+	if !all.EnablesInvestment("i1") {
+		t.Error("an empty list should enable every investment")
+	}
+
+	some := TenantConfig{EnabledInvestmentIDs: []string{"i1"}}
+	if !some.EnablesInvestment("i1") || some.EnablesInvestment("i2") {
+		t.Error("a list should enable only the investments it names")
+	}
+}
+
+func TestCreateTenantRequestValidate(t *testing.T) {
+	negative, whole := -0.01, 1.0
+
+	tests := []struct {
+		name string
+		req  CreateTenantRequest
+		want error
+	}{
+		{"valid", CreateTenantRequest{ID: "acme", Name: "Acme", Config: TenantConfig{CutOffTime: "15:30"}}, nil},
+		{"uppercase id", CreateTenantRequest{ID: "Acme", Name: "Acme"}, ErrInvalidTenantConfig},
+		{"missing name", CreateTenantRequest{ID: "acme", Name: " "}, ErrInvalidTenantConfig},
+		{"negative fee", CreateTenantRequest{ID: "acme", Name: "Acme", Config: TenantConfig{SubscriptionFeeRate: &negative}}, ErrInvalidTenantConfig},
+		{"whole fee", CreateTenantRequest{ID: "acme", Name: "Acme", Config: TenantConfig{RedemptionFeeRate: &whole}}, ErrInvalidTenantConfig},
+		{"bad cut-off", CreateTenantRequest{ID: "acme", Name: "Acme", Config: TenantConfig{CutOffTime: "3pm"}}, ErrInvalidTenantConfig},
+		{"empty investment", CreateTenantRequest{ID: "acme", Name: "Acme", Config: TenantConfig{EnabledInvestmentIDs: []string{""}}}, ErrInvalidTenantConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestTenantFromContext(t *testing.T) {
+	if got := TenantFromContext(context.Background()); got != DefaultTenantID {
+		t.Errorf("TenantFromContext() without a tenant = %q, want %q", got, DefaultTenantID)
+	}
+	if got := TenantFromContext(WithTenant(context.Background(), "acme")); got != "acme" {
+		t.Errorf("TenantFromContext() = %q, want acme", got)
+	}
+}
+
+func TestPrincipalHomeTenant(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal Principal
+		want      string
+	}{
+		{"Own tenant", Principal{TenantID: "acme", Roles: []string{RoleOperator}}, "acme"},
+		{"Staff without a tenant", Principal{Roles: []string{RoleOperator}}, DefaultTenantID},
+		{"Customer without a tenant", Principal{CustomerID: "c1", Roles: []string{RoleCustomer}}, DefaultTenantID},
+		{"Platform admin of a tenant", Principal{TenantID: "acme", Roles: []string{RolePlatformAdmin}}, "acme"},
+		{"Platform admin without a tenant", Principal{Roles: []string{RolePlatformAdmin}}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.HomeTenant(); got != tt.want {
+				t.Errorf("HomeTenant() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
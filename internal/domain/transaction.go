@@ -2,37 +2,67 @@ package domain
 
 import "time"
 
+const (
+	TransactionPending   = "PENDING"
+	TransactionCompleted = "COMPLETED"
+	TransactionReversed  = "REVERSED"
+)
+
 type Transaction struct {
-	ID              string    `json:"id"`
-	CustomerID      string    `json:"customer_id"`
-	InvestmentID    string    `json:"investment_id"`
-	Type            string    `json:"type"` // DEPOSIT or WITHDRAW
-	Amount          float64   `json:"amount"`
-	Units           float64   `json:"units"`
-	NAB             float64   `json:"nab"`
-	TransactionDate time.Time `json:"transaction_date"`
+	ID                string     `json:"id"`
+	CustomerID        string     `json:"customer_id"`
+	InvestmentID      string     `json:"investment_id"`
+	InvestmentName    string     `json:"investment_name,omitempty"`
+	Type              string     `json:"type"`   // DEPOSIT, WITHDRAW or REVERSAL
+	Status            string     `json:"status"` // Withdrawals stay PENDING until their proceeds settle
+	Amount            float64    `json:"amount"`
+	Fee               float64    `json:"fee"`
+	Units             float64    `json:"units"`
+	NAB               float64    `json:"nab"`
+	NABDate           string     `json:"nab_date,omitempty"` // Date of the NAB the transaction was priced at
+	BalanceBefore     float64    `json:"balance_before"`     // Holding value before the transaction, at its NAB
+	BalanceAfter      float64    `json:"balance_after"`      // Holding value after the transaction, at its NAB
+	RiskAcknowledged  bool       `json:"risk_acknowledged"`  // Customer accepted a risk above their profile
+	TransactionDate   time.Time  `json:"transaction_date"`
+	SettlementDate    *time.Time `json:"settlement_date,omitempty"` // When withdrawal proceeds are credited to the wallet
+	CompletedDate     *time.Time `json:"completed_date,omitempty"`
+	ReversesID        string     `json:"reverses_id,omitempty"`        // Transaction a REVERSAL compensates
+	Reason            string     `json:"reason,omitempty"`             // Why a REVERSAL was made
+	ExternalReference string     `json:"external_reference,omitempty"` // Partner's order reference, unique across transactions
 }
 
 type DepositRequest struct {
-	CustomerID   string  `json:"customer_id"`
-	InvestmentID string  `json:"investment_id"`
-	Amount       float64 `json:"amount"`
+	CustomerID        string  `json:"customer_id"`
+	InvestmentID      string  `json:"investment_id"`
+	Amount            float64 `json:"amount"`
+	RiskAcknowledged  bool    `json:"risk_acknowledged"`
+	ExternalReference string  `json:"external_reference"` // Optional, orders reusing a reference are rejected
 }
 
 type WithdrawRequest struct {
-	CustomerID   string  `json:"customer_id"`
-	InvestmentID string  `json:"investment_id"`
-	Amount       float64 `json:"amount"`
+	CustomerID        string  `json:"customer_id"`
+	InvestmentID      string  `json:"investment_id"`
+	Amount            float64 `json:"amount"`
+	Units             float64 `json:"units"`              // Redeem this many units instead of Amount
+	RedeemAll         bool    `json:"redeem_all"`         // Redeem the whole holding, Amount and Units are ignored
+	ExternalReference string  `json:"external_reference"` // Optional, orders reusing a reference are rejected
+}
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason"`
 }
 
 type TransactionResponse struct {
 	TransactionID  string  `json:"transaction_id"`
 	Message        string  `json:"message"`
 	Amount         float64 `json:"amount"`
+	Fee            float64 `json:"fee"`
 	Units          float64 `json:"units_added,omitempty"`
 	UnitsReduced   float64 `json:"units_reduced,omitempty"`
 	NAB            float64 `json:"nab"`
 	TotalUnits     float64 `json:"total_units,omitempty"`
 	RemainingUnits float64 `json:"remaining_units,omitempty"`
 	CurrentBalance float64 `json:"current_balance"`
+	Status         string  `json:"status"`
+	WalletBalance  float64 `json:"wallet_balance"`
 }
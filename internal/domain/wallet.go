@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Wallet is the cash account a customer funds subscriptions from and
+// receives redemption proceeds into
+type Wallet struct {
+	CustomerID     string    `json:"customer_id"`
+	Balance        float64   `json:"balance"`         // Cash available for subscriptions and payouts
+	PendingBalance float64   `json:"pending_balance"` // Redemption proceeds awaiting settlement
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type WalletMovementType string
+
+const (
+	MovementTopUp        WalletMovementType = "TOP_UP"
+	MovementPayout       WalletMovementType = "PAYOUT"
+	MovementSubscription WalletMovementType = "SUBSCRIPTION"
+	MovementRedemption   WalletMovementType = "REDEMPTION"
+	MovementReversal     WalletMovementType = "REVERSAL"
+)
+
+// WalletMovement is one entry of a wallet's ledger. Amount is positive for
+// credits and negative for debits.
+type WalletMovement struct {
+	ID            string             `json:"id"`
+	CustomerID    string             `json:"customer_id"`
+	Type          WalletMovementType `json:"type"`
+	Amount        float64            `json:"amount"`
+	BalanceAfter  float64            `json:"balance_after"`
+	TransactionID string             `json:"transaction_id,omitempty"` // Transaction that moved the cash
+	Reference     string             `json:"reference,omitempty"`      // External reference of a top-up or payout
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type WalletFundsRequest struct {
+	Amount    float64 `json:"amount"`
+	Reference string  `json:"reference"`
+}
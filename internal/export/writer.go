@@ -0,0 +1,139 @@
+// Package export encodes dataset rows as CSV, JSON lines or Parquet
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"nobi-assesment/internal/domain"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRowGroupRows bounds the rows of a Parquet row group. Row groups are
+// buffered in memory until they are complete, so this also bounds the memory
+// an export takes.
+const parquetRowGroupRows = 10000
+
+// Writer encodes rows one at a time. Close must be called after the last row
+// to flush buffered rows and, for Parquet, write the file footer.
+type Writer[T any] interface {
+	Write(row *T) error
+	Close() error
+}
+
+// NewWriter returns a writer of rows of the struct type T in the given format
+func NewWriter[T any](format domain.ExportFormat, w io.Writer) (Writer[T], error) {
+	switch format {
+	case domain.ExportCSV:
+		return newCSVWriter[T](w)
+	case domain.ExportJSONL:
+		return &jsonlWriter[T]{encoder: json.NewEncoder(w)}, nil
+	case domain.ExportParquet:
+		return newParquetWriter[T](w, parquetRowGroupRows), nil
+	default:
+		return nil, domain.ErrInvalidExportFormat
+	}
+}
+
+// ContentType is the media type of an export file
+func ContentType(format domain.ExportFormat) string {
+	switch format {
+	case domain.ExportCSV:
+		return "text/csv"
+	case domain.ExportJSONL:
+		return "application/jsonl"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+type jsonlWriter[T any] struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonlWriter[T]) Write(row *T) error {
+	return w.encoder.Encode(row)
+}
+
+func (w *jsonlWriter[T]) Close() error {
+	return nil
+}
+
+type parquetWriter[T any] struct {
+	writer *parquet.GenericWriter[T]
+}
+
+// newParquetWriter returns a writer flushing a row group every rowGroupRows
+// rows
+func newParquetWriter[T any](w io.Writer, rowGroupRows int64) *parquetWriter[T] {
+	return &parquetWriter[T]{writer: parquet.NewGenericWriter[T](w, parquet.MaxRowsPerRowGroup(rowGroupRows))}
+}
+
+func (w *parquetWriter[T]) Write(row *T) error {
+	_, err := w.writer.Write([]T{*row})
+	return err
+}
+
+func (w *parquetWriter[T]) Close() error {
+	return w.writer.Close()
+}
+
+// csvWriter writes a header naming the fields by their JSON names, then one
+// record per row. Times are formatted as RFC 3339 and nil pointers as empty
+// values.
+type csvWriter[T any] struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter[T any](w io.Writer) (*csvWriter[T], error) {
+	rowType := reflect.TypeOf((*T)(nil)).Elem()
+	header := make([]string, rowType.NumField())
+	for i := range header {
+		header[i], _, _ = strings.Cut(rowType.Field(i).Tag.Get("json"), ",")
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvWriter[T]{writer: writer, record: make([]string, len(header))}, nil
+}
+
+func (w *csvWriter[T]) Write(row *T) error {
+	value := reflect.ValueOf(row).Elem()
+	for i := range w.record {
+		w.record[i] = formatField(value.Field(i))
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter[T]) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func formatField(field reflect.Value) string {
+	if field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return ""
+		}
+		field = field.Elem()
+	}
+
+	switch value := field.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		return value
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,37 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+type testRow struct {
+	ID     string  `json:"id" parquet:"id"`
+	Amount float64 `json:"amount" parquet:"amount"`
+}
+
+func TestParquetWriterRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newParquetWriter[testRow](&buf, 100)
+	for i := 0; i < 250; i++ {
+		if err := writer.Write(&testRow{ID: "row", Amount: float64(i)}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	if got := len(file.RowGroups()); got != 3 {
+		t.Errorf("row groups = %d, want 3", got)
+	}
+	if got := file.NumRows(); got != 250 {
+		t.Errorf("rows = %d, want 250", got)
+	}
+}
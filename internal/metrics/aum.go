@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TenantLister lists every tenant
+type TenantLister interface {
+	GetAll(ctx context.Context) ([]*domain.Tenant, error)
+}
+
+// InvestmentLister lists the investments of the context's tenant
+type InvestmentLister interface {
+	GetAll(ctx context.Context) ([]*domain.Investment, error)
+}
+
+var aumDesc = prometheus.NewDesc(
+	namespace+"_investment_aum",
+	"Total balance under management of each investment.",
+	[]string{"tenant_id", "investment_id"}, nil,
+)
+
+// aumCollector reads the total balance of every investment of every tenant
+// when scraped. Tenants that cannot be read are logged and left out rather
+// than failing the whole scrape.
+type aumCollector struct {
+	tenants     TenantLister
+	investments InvestmentLister
+	timeout     time.Duration
+}
+
+// RegisterAUM adds a gauge of the total balance of each investment, read on
+// every scrape and given up after the timeout
+func RegisterAUM(tenants TenantLister, investments InvestmentLister, timeout time.Duration) {
+	Registry.MustRegister(NewAUMCollector(tenants, investments, timeout))
+}
+
+// NewAUMCollector returns the collector behind RegisterAUM
+func NewAUMCollector(tenants TenantLister, investments InvestmentLister, timeout time.Duration) prometheus.Collector {
+	return &aumCollector{tenants: tenants, investments: investments, timeout: timeout}
+}
+
+func (c *aumCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- aumDesc
+}
+
+func (c *aumCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	tenants, err := c.tenants.GetAll(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list tenants for metrics", "error", err)
+		return
+	}
+
+	for _, tenant := range tenants {
+		investments, err := c.investments.GetAll(domain.WithTenant(ctx, tenant.ID))
+		if err != nil {
+			slog.WarnContext(ctx, "failed to list investments for metrics", "tenant_id", tenant.ID, "error", err)
+			continue
+		}
+		for _, investment := range investments {
+			ch <- prometheus.MustNewConstMetric(aumDesc, prometheus.GaugeValue, investment.TotalBalance, tenant.ID, investment.ID)
+		}
+	}
+}
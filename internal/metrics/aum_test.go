@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeTenants []*domain.Tenant
+
+func (f fakeTenants) GetAll(ctx context.Context) ([]*domain.Tenant, error) {
+	return f, nil
+}
+
+type fakeInvestments map[string][]*domain.Investment
+
+func (f fakeInvestments) GetAll(ctx context.Context) ([]*domain.Investment, error) {
+	tenantID := domain.TenantFromContext(ctx)
+	if tenantID == "broken" {
+		return nil, errors.New("connection refused")
+	}
+	return f[tenantID], nil
+}
+
+func TestAUMCollector(t *testing.T) {
+	collector := NewAUMCollector(
+		fakeTenants{{ID: "default"}, {ID: "broken"}, {ID: "acme"}},
+		fakeInvestments{
+			"default": {{ID: "inv-1", TotalBalance: 1500000}, {ID: "inv-2", TotalBalance: 0}},
+			"acme":    {{ID: "inv-3", TotalBalance: 250.5}},
+		},
+		time.Second,
+	)
+
+	// The tenant that cannot be read is left out
+	want := `
+# HELP nobi_investment_aum Total balance under management of each investment.
+# TYPE nobi_investment_aum gauge
+nobi_investment_aum{investment_id="inv-1",tenant_id="default"} 1.5e+06
+nobi_investment_aum{investment_id="inv-2",tenant_id="default"} 0
+nobi_investment_aum{investment_id="inv-3",tenant_id="acme"} 250.5
+`
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "nobi"
+
+// Order types of the order metrics
+const (
+	OrderDeposit  = "deposit"
+	OrderWithdraw = "withdraw"
+)
+
+// Registry holds every metric served by Handler, along with the Go runtime
+// and process metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to answer HTTP requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	orders = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "orders_total",
+		Help:      "Deposits and withdrawals placed, by type and investment.",
+	}, []string{"type", "investment_id"})
+
+	orderAmount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "order_amount_total",
+		Help:      "Amount of the deposits and withdrawals placed, by type and investment.",
+	}, []string{"type", "investment_id"})
+
+	failures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "failures_total",
+		Help:      "Requests answered with an error, by error code.",
+	}, []string{"code"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration,
+		orders,
+		orderAmount,
+		failures,
+	)
+}
+
+// ObserveRequest records the time taken to answer a request
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	requestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+// RecordOrder counts a deposit or withdrawal placed in the investment
+func RecordOrder(orderType, investmentID string, amount float64) {
+	orders.WithLabelValues(orderType, investmentID).Inc()
+	orderAmount.WithLabelValues(orderType, investmentID).Add(amount)
+}
+
+// RecordFailure counts a request answered with the error code
+func RecordFailure(code string) {
+	failures.WithLabelValues(code).Inc()
+}
+
+// RegisterDBStats adds the connection pool statistics of db
+func RegisterDBStats(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, "nobi_investment"))
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Serve serves the metrics at /metrics on their own address, kept apart from
+// the API as they cover every tenant
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	return server.ListenAndServe()
+}
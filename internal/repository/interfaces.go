@@ -3,20 +3,39 @@ package repository
 import (
 	"context"
 	"nobi-assesment/internal/domain"
+	"time"
 )
 
+// Transactor runs fn in a database transaction. Repository calls made with
+// the context passed to fn take part in the transaction, which is committed
+// when fn returns nil and rolled back otherwise.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *domain.Customer) error
 	GetByID(ctx context.Context, id string) (*domain.Customer, error)
 	GetAll(ctx context.Context) ([]*domain.Customer, error)
+	// Update saves the profile of a customer, leaving its KYC status alone
+	Update(ctx context.Context, customer *domain.Customer) error
 	UpdateActiveStatus(ctx context.Context, id string, isActive bool) error
+	// UpdateKYCStatus moves a customer from one KYC status to another, reporting false when it was no longer in from
+	UpdateKYCStatus(ctx context.Context, id string, from, to domain.KYCStatus) (bool, error)
+	// InvalidateKYC returns a pending or verified customer to unverified
+	InvalidateKYC(ctx context.Context, id string) error
 }
 
 type InvestmentRepository interface {
 	Create(ctx context.Context, investment *domain.Investment) error
 	GetByID(ctx context.Context, id string) (*domain.Investment, error)
 	GetAll(ctx context.Context) ([]*domain.Investment, error)
+	// GetAllForUpdate returns every investment, locking them until the end of the transaction
+	GetAllForUpdate(ctx context.Context) ([]*domain.Investment, error)
+	Update(ctx context.Context, investment *domain.Investment) error
 	UpdateBalance(ctx context.Context, id string, amountChange float64, unitsChange float64) error
+	// RecordNAB stores the NAB the investment was priced at on a day, replacing any earlier NAB of that day
+	RecordNAB(ctx context.Context, id, date string, nab float64) error
 }
 
 type CustomerInvestmentRepository interface {
@@ -24,9 +43,111 @@ type CustomerInvestmentRepository interface {
 	GetByCustomerAndInvestment(ctx context.Context, customerID, investmentID string) (*domain.CustomerInvestment, error)
 	UpdateUnits(ctx context.Context, id string, unitsChange float64) error
 	GetCustomerPortfolio(ctx context.Context, customerID, investmentID string) (*domain.CustomerPortfolio, error)
+	GetAll(ctx context.Context) ([]*domain.CustomerInvestment, error)
+	// GetAllForUpdate returns every holding, locking them until the end of the transaction
+	GetAllForUpdate(ctx context.Context) ([]*domain.CustomerInvestment, error)
 }
 
 type TransactionRepository interface {
 	Create(ctx context.Context, transaction *domain.Transaction) error
+	GetByID(ctx context.Context, id string) (*domain.Transaction, error)
+	GetByExternalReference(ctx context.Context, reference string) (*domain.Transaction, error)
 	GetByCustomerID(ctx context.Context, customerID string) ([]*domain.Transaction, error)
+	// GetHistory returns a customer's transactions made before the given time, oldest first
+	GetHistory(ctx context.Context, customerID string, before time.Time) ([]*domain.Transaction, error)
+	// GetLatestNAB returns the NAB and date of an investment's last completed deposit or withdrawal made before the given time
+	GetLatestNAB(ctx context.Context, investmentID string, before time.Time) (float64, time.Time, error)
+	// SumAmountSince totals the pending and completed orders of a type placed since a time, leaving out reversed ones
+	SumAmountSince(ctx context.Context, customerID, investmentID, transactionType string, since time.Time) (float64, error)
+	// GetDueSettlements returns pending withdrawals whose settlement date has passed
+	GetDueSettlements(ctx context.Context, now time.Time, limit int) ([]*domain.Transaction, error)
+	// GetPositions nets the units and fund cash of every pending or completed transaction per customer and investment
+	GetPositions(ctx context.Context) ([]*domain.TransactionPosition, error)
+	// MarkCompleted completes a pending transaction, reporting false if it was no longer pending
+	MarkCompleted(ctx context.Context, id string, completedAt time.Time) (bool, error)
+	// MarkReversed moves a transaction from the given status to REVERSED, reporting false if its status had changed
+	MarkReversed(ctx context.Context, id, fromStatus string) (bool, error)
+}
+
+type AuditLogRepository interface {
+	// Create appends the log to the tenant's hash chain, setting its sequence and hashes
+	Create(ctx context.Context, log *domain.AuditLog) error
+	// GetAll returns the logs matching the filter, newest first
+	GetAll(ctx context.Context, filter *domain.AuditLogFilter) ([]*domain.AuditLog, error)
+	// GetChainHead returns the sequence and hash of the tenant's last log
+	GetChainHead(ctx context.Context) (int64, string, error)
+	// StreamChain hands every log of the tenant to fn in chain order
+	StreamChain(ctx context.Context, fn func(*domain.AuditLog) error) error
+}
+
+type RiskProfileRepository interface {
+	Create(ctx context.Context, profile *domain.CustomerRiskProfile) error
+	GetLatestByCustomer(ctx context.Context, customerID string) (*domain.CustomerRiskProfile, error)
+}
+
+type RecurringPlanRepository interface {
+	Create(ctx context.Context, plan *domain.RecurringPlan) error
+	GetByID(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	GetAll(ctx context.Context, customerID string) ([]*domain.RecurringPlan, error)
+	// GetDue returns active plans scheduled on or before today whose retry time, if any, has passed
+	GetDue(ctx context.Context, today string, now time.Time, limit int) ([]*domain.RecurringPlan, error)
+	// GetForUpdate returns a plan, locking it until the end of the transaction
+	GetForUpdate(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	// Claim locks an active plan until the given time so only one worker runs it, reporting whether the lock was taken
+	Claim(ctx context.Context, id string, now, until time.Time) (bool, error)
+	// Update saves the plan, leaving any claim on it in place
+	Update(ctx context.Context, plan *domain.RecurringPlan) error
+	// Release saves a plan claimed until the given time and releases the claim, reporting false, without saving,
+	// when the claim was lost
+	Release(ctx context.Context, plan *domain.RecurringPlan, claimedUntil time.Time) (bool, error)
+	CreateRun(ctx context.Context, run *domain.RecurringPlanRun) error
+	GetRuns(ctx context.Context, planID string) ([]*domain.RecurringPlanRun, error)
+}
+
+type WalletRepository interface {
+	GetByCustomerID(ctx context.Context, customerID string) (*domain.Wallet, error)
+	GetAll(ctx context.Context) ([]*domain.Wallet, error)
+	// GetForUpdate returns the customer's wallet, opening an empty one if needed,
+	// and locks it for the rest of the transaction
+	GetForUpdate(ctx context.Context, customerID string) (*domain.Wallet, error)
+	AdjustBalance(ctx context.Context, customerID string, balanceChange, pendingChange float64) error
+	CreateMovement(ctx context.Context, movement *domain.WalletMovement) error
+	GetMovements(ctx context.Context, customerID string) ([]*domain.WalletMovement, error)
+}
+
+type LedgerRepository interface {
+	// Post stores a journal entry with its lines
+	Post(ctx context.Context, entry *domain.JournalEntry) error
+	// GetEntries returns journal entries, optionally only those of a transaction or touching a customer's accounts
+	GetEntries(ctx context.Context, transactionID, customerID string) ([]*domain.JournalEntry, error)
+	// GetBalances sums the journal into a balance per account and asset, optionally only entries of one type
+	GetBalances(ctx context.Context, entryType domain.JournalEntryType) ([]*domain.AccountBalance, error)
+}
+
+// ExportRepository streams rows updated at or after a time, oldest update
+// first, handing each to fn as it is read instead of loading them all
+type ExportRepository interface {
+	StreamTransactions(ctx context.Context, since time.Time, fn func(*domain.TransactionExport) error) error
+	StreamHoldings(ctx context.Context, since time.Time, fn func(*domain.HoldingExport) error) error
+	StreamNABHistory(ctx context.Context, since time.Time, fn func(*domain.NABExport) error) error
+}
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error)
+	GetAll(ctx context.Context) ([]*domain.APIKey, error)
+	// SetExpiry sets when the key stops working, used for revocation and rotation
+	SetExpiry(ctx context.Context, id string, expiresAt, revokedAt *time.Time) error
+	TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error
+}
+
+// TenantRepository stores the tenants themselves, which are not scoped to a
+// tenant
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *domain.Tenant) error
+	GetByID(ctx context.Context, id string) (*domain.Tenant, error)
+	GetByHost(ctx context.Context, host string) (*domain.Tenant, error)
+	GetAll(ctx context.Context) ([]*domain.Tenant, error)
+	Update(ctx context.Context, tenant *domain.Tenant) error
 }
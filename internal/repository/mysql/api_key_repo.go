@@ -0,0 +1,140 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"time"
+)
+
+const apiKeyColumns = `id, tenant_id, name, prefix, key_hash, permissions, customer_ids, require_signature, rotated_from_id,
+	expires_at, revoked_at, last_used_at, created_at, updated_at`
+
+type mysqlAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &mysqlAPIKeyRepository{db}
+}
+
+func (r *mysqlAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	permissions, err := json.Marshal(key.Permissions)
+	if err != nil {
+		return err
+	}
+	customerIDs, err := json.Marshal(key.CustomerIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, permissions, customer_ids, require_signature,
+			rotated_from_id, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
+		key.ID,
+		tenantID(ctx),
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		string(permissions),
+		string(customerIDs),
+		key.RequireSignature,
+		nullString(key.RotatedFromID),
+		key.ExpiresAt)
+	return err
+}
+
+func (r *mysqlAPIKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE id = ? AND tenant_id = ?"
+
+	return scanAPIKey(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
+
+// GetByPrefix looks keys up in every tenant, keys are authenticated before
+// the tenant of the request is known
+func (r *mysqlAPIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*domain.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE prefix = ?"
+
+	return scanAPIKey(executor(ctx, r.db).QueryRowContext(ctx, query, prefix))
+}
+
+func (r *mysqlAPIKeyRepository) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys WHERE tenant_id = ? ORDER BY created_at DESC"
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*domain.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *mysqlAPIKeyRepository) SetExpiry(ctx context.Context, id string, expiresAt, revokedAt *time.Time) error {
+	query := "UPDATE api_keys SET expires_at = ?, revoked_at = ? WHERE id = ? AND tenant_id = ?"
+
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, expiresAt, revokedAt, id, tenantID(ctx))
+	return err
+}
+
+func (r *mysqlAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	// updated_at tracks changes to the key, not its use
+	query := "UPDATE api_keys SET last_used_at = ?, updated_at = updated_at WHERE id = ?"
+
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, usedAt, id)
+	return err
+}
+
+// scanAPIKey reads a row selected with apiKeyColumns
+func scanAPIKey(row interface{ Scan(...any) error }) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var permissions, customerIDs []byte
+	var rotatedFromID sql.NullString
+	var expiresAt, revokedAt, lastUsedAt sql.NullTime
+
+	err := row.Scan(
+		&key.ID,
+		&key.TenantID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&permissions,
+		&customerIDs,
+		&key.RequireSignature,
+		&rotatedFromID,
+		&expiresAt,
+		&revokedAt,
+		&lastUsedAt,
+		&key.CreatedAt,
+		&key.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(permissions, &key.Permissions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(customerIDs, &key.CustomerIDs); err != nil {
+		return nil, err
+	}
+	key.RotatedFromID = rotatedFromID.String
+	key.ExpiresAt = nullTimePtr(expiresAt)
+	key.RevokedAt = nullTimePtr(revokedAt)
+	key.LastUsedAt = nullTimePtr(lastUsedAt)
+
+	return &key, nil
+}
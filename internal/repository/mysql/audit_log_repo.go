@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"time"
+)
+
+const auditLogColumns = `id, sequence, entity_type, entity_id, action, reason, actor, request_id, ip_address,
+	before_data, after_data, prev_hash, hash, created_at`
+
+type mysqlAuditLogRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLAuditLogRepository(db *sql.DB) repository.AuditLogRepository {
+	return &mysqlAuditLogRepository{db}
+}
+
+// Create locks the head of the tenant's chain until the surrounding
+// transaction ends, so logs are chained one at a time and a rolled back
+// change leaves no log behind
+func (r *mysqlAuditLogRepository) Create(ctx context.Context, log *domain.AuditLog) error {
+	return NewMySQLTransactor(r.db).WithinTransaction(ctx, func(ctx context.Context) error {
+		db := executor(ctx, r.db)
+		tenant := tenantID(ctx)
+
+		if _, err := db.ExecContext(ctx, "INSERT IGNORE INTO audit_chain_heads (tenant_id) VALUES (?)", tenant); err != nil {
+			return err
+		}
+		var sequence int64
+		var prevHash string
+		err := db.QueryRowContext(ctx, "SELECT sequence, hash FROM audit_chain_heads WHERE tenant_id = ? FOR UPDATE", tenant).
+			Scan(&sequence, &prevHash)
+		if err != nil {
+			return err
+		}
+
+		log.Sequence = sequence + 1
+		log.PrevHash = prevHash
+		// Stored with microsecond precision, which the hash must match
+		log.CreatedAt = log.CreatedAt.UTC().Truncate(time.Microsecond)
+		log.Hash = log.ComputeHash()
+
+		query := `
+			INSERT INTO audit_logs (id, tenant_id, sequence, entity_type, entity_id, action, reason, actor, request_id,
+				ip_address, before_data, after_data, prev_hash, hash, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`
+		_, err = db.ExecContext(ctx, query,
+			log.ID,
+			tenant,
+			log.Sequence,
+			log.EntityType,
+			log.EntityID,
+			log.Action,
+			nullString(log.Reason),
+			log.Actor,
+			nullString(log.RequestID),
+			nullString(log.IPAddress),
+			nullJSON(log.Before),
+			nullJSON(log.After),
+			log.PrevHash,
+			log.Hash,
+			log.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, "UPDATE audit_chain_heads SET sequence = ?, hash = ? WHERE tenant_id = ?",
+			log.Sequence, log.Hash, tenant)
+		return err
+	})
+}
+
+func (r *mysqlAuditLogRepository) GetAll(ctx context.Context, filter *domain.AuditLogFilter) ([]*domain.AuditLog, error) {
+	query := "SELECT " + auditLogColumns + " FROM audit_logs WHERE tenant_id = ?"
+	args := []any{tenantID(ctx)}
+	for _, condition := range []struct{ column, value string }{
+		{"entity_type", filter.EntityType},
+		{"entity_id", filter.EntityID},
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+	} {
+		if condition.value != "" {
+			query += " AND " + condition.column + " = ?"
+			args = append(args, condition.value)
+		}
+	}
+	if !filter.From.IsZero() {
+		query += " AND created_at >= ?"
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query += " AND created_at < ?"
+		args = append(args, filter.To.UTC())
+	}
+	query += " ORDER BY sequence DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*domain.AuditLog{}
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (r *mysqlAuditLogRepository) GetChainHead(ctx context.Context) (int64, string, error) {
+	var sequence int64
+	var hash string
+	err := executor(ctx, r.db).QueryRowContext(ctx, "SELECT sequence, hash FROM audit_chain_heads WHERE tenant_id = ?", tenantID(ctx)).
+		Scan(&sequence, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		// No log was written for the tenant yet
+		return 0, "", nil
+	}
+	return sequence, hash, err
+}
+
+func (r *mysqlAuditLogRepository) StreamChain(ctx context.Context, fn func(*domain.AuditLog) error) error {
+	query := "SELECT " + auditLogColumns + " FROM audit_logs WHERE tenant_id = ? ORDER BY sequence"
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// scanAuditLog reads a row selected with auditLogColumns
+func scanAuditLog(row interface{ Scan(...any) error }) (*domain.AuditLog, error) {
+	var log domain.AuditLog
+	var reason, requestID, ipAddress sql.NullString
+	var before, after []byte
+
+	if err := row.Scan(
+		&log.ID,
+		&log.Sequence,
+		&log.EntityType,
+		&log.EntityID,
+		&log.Action,
+		&reason,
+		&log.Actor,
+		&requestID,
+		&ipAddress,
+		&before,
+		&after,
+		&log.PrevHash,
+		&log.Hash,
+		&log.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	log.Reason = reason.String
+	log.RequestID = requestID.String
+	log.IPAddress = ipAddress.String
+	log.Before = before
+	log.After = after
+	return &log, nil
+}
@@ -18,7 +18,7 @@ func NewMySQLCustomerInvestmentRepository(db *sql.DB) repository.CustomerInvestm
 
 func (r *mysqlCustomerInvestmentRepository) Create(ctx context.Context, customerInvestment *domain.CustomerInvestment) error {
 	query := "INSERT INTO customer_investments (id, customer_id, investment_id, units) VALUES (?, ?, ?, ?)"
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
 		customerInvestment.ID,
 		customerInvestment.CustomerID,
 		customerInvestment.InvestmentID,
@@ -28,13 +28,14 @@ func (r *mysqlCustomerInvestmentRepository) Create(ctx context.Context, customer
 
 func (r *mysqlCustomerInvestmentRepository) GetByCustomerAndInvestment(ctx context.Context, customerID, investmentID string) (*domain.CustomerInvestment, error) {
 	query := `
-		SELECT id, customer_id, investment_id, units 
-		FROM customer_investments 
-		WHERE customer_id = ? AND investment_id = ?
+		SELECT ci.id, ci.customer_id, ci.investment_id, ci.units
+		FROM customer_investments ci
+		JOIN customers c ON c.id = ci.customer_id
+		WHERE ci.customer_id = ? AND ci.investment_id = ? AND c.tenant_id = ?
 	`
 
 	var customerInvestment domain.CustomerInvestment
-	err := r.db.QueryRowContext(ctx, query, customerID, investmentID).Scan(
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, customerID, investmentID, tenantID(ctx)).Scan(
 		&customerInvestment.ID,
 		&customerInvestment.CustomerID,
 		&customerInvestment.InvestmentID,
@@ -48,23 +49,23 @@ func (r *mysqlCustomerInvestmentRepository) GetByCustomerAndInvestment(ctx conte
 
 func (r *mysqlCustomerInvestmentRepository) UpdateUnits(ctx context.Context, id string, unitsChange float64) error {
 	query := "UPDATE customer_investments SET units = units + ? WHERE id = ?"
-	_, err := r.db.ExecContext(ctx, query, unitsChange, id)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, unitsChange, id)
 	return err
 }
 
 func (r *mysqlCustomerInvestmentRepository) GetCustomerPortfolio(ctx context.Context, customerID, investmentID string) (*domain.CustomerPortfolio, error) {
 	// Get customer
 	var customer domain.Customer
-	customerQuery := "SELECT id, name FROM customers WHERE id = ?"
-	err := r.db.QueryRowContext(ctx, customerQuery, customerID).Scan(&customer.ID, &customer.Name)
+	customerQuery := "SELECT id, name FROM customers WHERE id = ? AND tenant_id = ?"
+	err := executor(ctx, r.db).QueryRowContext(ctx, customerQuery, customerID, tenantID(ctx)).Scan(&customer.ID, &customer.Name)
 	if err != nil {
 		return nil, err
 	}
 
 	// Get investment
 	var investment domain.Investment
-	investmentQuery := "SELECT id, name, total_units, total_balance FROM investments WHERE id = ?"
-	err = r.db.QueryRowContext(ctx, investmentQuery, investmentID).Scan(
+	investmentQuery := "SELECT id, name, total_units, total_balance FROM investments WHERE id = ? AND tenant_id = ?"
+	err = executor(ctx, r.db).QueryRowContext(ctx, investmentQuery, investmentID, tenantID(ctx)).Scan(
 		&investment.ID, &investment.Name, &investment.TotalUnits, &investment.TotalBalance)
 	if err != nil {
 		return nil, err
@@ -84,7 +85,7 @@ func (r *mysqlCustomerInvestmentRepository) GetCustomerPortfolio(ctx context.Con
 	`
 	var units float64
 	var portfolioID string
-	err = r.db.QueryRowContext(ctx, query, customerID, investmentID).Scan(&portfolioID, &units)
+	err = executor(ctx, r.db).QueryRowContext(ctx, query, customerID, investmentID).Scan(&portfolioID, &units)
 	if err != nil {
 		return nil, err
 	}
@@ -95,3 +96,41 @@ func (r *mysqlCustomerInvestmentRepository) GetCustomerPortfolio(ctx context.Con
 
 	return &portfolio, nil
 }
+
+const customerInvestmentsOfTenant = `
+	SELECT ci.id, ci.customer_id, ci.investment_id, ci.units
+	FROM customer_investments ci
+	JOIN customers c ON c.id = ci.customer_id
+	WHERE c.tenant_id = ?`
+
+func (r *mysqlCustomerInvestmentRepository) GetAll(ctx context.Context) ([]*domain.CustomerInvestment, error) {
+	return r.getAll(ctx, customerInvestmentsOfTenant)
+}
+
+func (r *mysqlCustomerInvestmentRepository) GetAllForUpdate(ctx context.Context) ([]*domain.CustomerInvestment, error) {
+	return r.getAll(ctx, customerInvestmentsOfTenant+" FOR UPDATE")
+}
+
+func (r *mysqlCustomerInvestmentRepository) getAll(ctx context.Context, query string) ([]*domain.CustomerInvestment, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	customerInvestments := []*domain.CustomerInvestment{}
+	for rows.Next() {
+		var customerInvestment domain.CustomerInvestment
+		if err := rows.Scan(
+			&customerInvestment.ID,
+			&customerInvestment.CustomerID,
+			&customerInvestment.InvestmentID,
+			&customerInvestment.Units); err != nil {
+			return nil, err
+		}
+
+		customerInvestments = append(customerInvestments, &customerInvestment)
+	}
+
+	return customerInvestments, rows.Err()
+}
@@ -8,6 +8,12 @@ import (
 	"nobi-assesment/pkg/utils"
 )
 
+const customerColumns = `c.id, c.name, c.email, c.phone, c.id_number, c.date_of_birth, c.address, c.kyc_status, c.is_active,
+	COALESCE(w.balance, 0), COALESCE(w.pending_balance, 0)`
+
+// customerTables joins the wallet so customers without one report no cash
+const customerTables = "customers c LEFT JOIN customer_wallets w ON w.customer_id = c.id"
+
 type mysqlCustomerRepository struct {
 	db *sql.DB
 }
@@ -17,16 +23,28 @@ func NewMySQLCustomerRepository(db *sql.DB) repository.CustomerRepository {
 }
 
 func (r *mysqlCustomerRepository) Create(ctx context.Context, customer *domain.Customer) error {
-	query := "INSERT INTO customers (id, name, is_active) VALUES (?, ?, ?)"
-	_, err := r.db.ExecContext(ctx, query, customer.ID, customer.Name, customer.IsActive)
-	return err
+	query := `
+		INSERT INTO customers (id, tenant_id, name, email, phone, id_number, date_of_birth, address, kyc_status, is_active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		customer.ID,
+		tenantID(ctx),
+		customer.Name,
+		nullString(customer.Email),
+		nullString(customer.Phone),
+		nullString(customer.IDNumber),
+		nullString(customer.DateOfBirth),
+		nullString(customer.Address),
+		customer.KYCStatus,
+		customer.IsActive)
+	return translateCustomerError(err)
 }
 
 func (r *mysqlCustomerRepository) GetByID(ctx context.Context, id string) (*domain.Customer, error) {
-	query := "SELECT id, name, is_active FROM customers WHERE id = ?"
+	query := "SELECT " + customerColumns + " FROM " + customerTables + " WHERE c.id = ? AND c.tenant_id = ?"
 
-	var customer domain.Customer
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&customer.ID, &customer.Name, &customer.IsActive)
+	customer, err := scanCustomer(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +56,7 @@ func (r *mysqlCustomerRepository) GetByID(ctx context.Context, id string) (*doma
 		JOIN investments i ON ci.investment_id = i.id
 		WHERE ci.customer_id = ?
 	`
-	rows, err := r.db.QueryContext(ctx, query, id)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, id)
 	if err != nil {
 		return nil, err
 	}
@@ -61,15 +79,14 @@ func (r *mysqlCustomerRepository) GetByID(ctx context.Context, id string) (*doma
 		totalUnits += units
 	}
 
-	customer.Balance = totalBalance
-	customer.Units = totalUnits
+	setCustomerBalances(customer, totalBalance, totalUnits)
 
-	return &customer, nil
+	return customer, nil
 }
 
 func (r *mysqlCustomerRepository) GetAll(ctx context.Context) ([]*domain.Customer, error) {
-	query := "SELECT id, name, is_active FROM customers"
-	rows, err := r.db.QueryContext(ctx, query)
+	query := "SELECT " + customerColumns + " FROM " + customerTables + " WHERE c.tenant_id = ?"
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -77,12 +94,12 @@ func (r *mysqlCustomerRepository) GetAll(ctx context.Context) ([]*domain.Custome
 
 	customers := []*domain.Customer{}
 	for rows.Next() {
-		var customer domain.Customer
-		if err := rows.Scan(&customer.ID, &customer.Name, &customer.IsActive); err != nil {
+		customer, err := scanCustomer(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		customers = append(customers, &customer)
+		customers = append(customers, customer)
 	}
 
 	// Calculate balance and units for each customer
@@ -93,7 +110,7 @@ func (r *mysqlCustomerRepository) GetAll(ctx context.Context) ([]*domain.Custome
 			JOIN investments i ON ci.investment_id = i.id
 			WHERE ci.customer_id = ?
 		`
-		investRows, err := r.db.QueryContext(ctx, query, customer.ID)
+		investRows, err := executor(ctx, r.db).QueryContext(ctx, query, customer.ID)
 		if err != nil {
 			continue
 		}
@@ -116,15 +133,100 @@ func (r *mysqlCustomerRepository) GetAll(ctx context.Context) ([]*domain.Custome
 		}
 		investRows.Close()
 
-		customer.Balance = totalBalance
-		customer.Units = totalUnits
+		setCustomerBalances(customer, totalBalance, totalUnits)
 	}
 
 	return customers, nil
 }
 
+func (r *mysqlCustomerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	query := `
+		UPDATE customers
+		SET name = ?, email = ?, phone = ?, id_number = ?, date_of_birth = ?, address = ?
+		WHERE id = ? AND tenant_id = ?
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		customer.Name,
+		nullString(customer.Email),
+		nullString(customer.Phone),
+		nullString(customer.IDNumber),
+		nullString(customer.DateOfBirth),
+		nullString(customer.Address),
+		customer.ID,
+		tenantID(ctx))
+	return translateCustomerError(err)
+}
+
 func (r *mysqlCustomerRepository) UpdateActiveStatus(ctx context.Context, id string, isActive bool) error {
-	query := "UPDATE customers SET is_active = ? WHERE id = ?"
-	_, err := r.db.ExecContext(ctx, query, isActive, id)
+	query := "UPDATE customers SET is_active = ? WHERE id = ? AND tenant_id = ?"
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, isActive, id, tenantID(ctx))
+	return err
+}
+
+func (r *mysqlCustomerRepository) UpdateKYCStatus(ctx context.Context, id string, from, to domain.KYCStatus) (bool, error) {
+	query := "UPDATE customers SET kyc_status = ? WHERE id = ? AND tenant_id = ? AND kyc_status = ?"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, to, id, tenantID(ctx), from)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *mysqlCustomerRepository) InvalidateKYC(ctx context.Context, id string) error {
+	query := "UPDATE customers SET kyc_status = ? WHERE id = ? AND tenant_id = ? AND kyc_status IN (?, ?)"
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, domain.KYCUnverified, id, tenantID(ctx), domain.KYCPending, domain.KYCVerified)
+	return err
+}
+
+// scanCustomer reads a row selected with customerColumns
+func scanCustomer(row interface{ Scan(...any) error }) (*domain.Customer, error) {
+	var customer domain.Customer
+	var email, phone, idNumber, address sql.NullString
+	var dateOfBirth sql.NullTime
+
+	err := row.Scan(
+		&customer.ID,
+		&customer.Name,
+		&email,
+		&phone,
+		&idNumber,
+		&dateOfBirth,
+		&address,
+		&customer.KYCStatus,
+		&customer.IsActive,
+		&customer.CashBalance,
+		&customer.PendingCash)
+	if err != nil {
+		return nil, err
+	}
+
+	customer.Email = email.String
+	customer.Phone = phone.String
+	customer.IDNumber = idNumber.String
+	customer.Address = address.String
+	if dateOfBirth.Valid {
+		customer.DateOfBirth = dateOfBirth.Time.Format(utils.DateLayout)
+	}
+
+	return &customer, nil
+}
+
+// setCustomerBalances fills in the invested value and the combined balance
+func setCustomerBalances(customer *domain.Customer, investedValue, units float64) {
+	customer.InvestedValue = investedValue
+	customer.Units = units
+	customer.Balance = utils.RoundDown(customer.CashBalance+customer.PendingCash+investedValue, 2)
+}
+
+// translateCustomerError maps unique key violations on customers to domain errors
+func translateCustomerError(err error) error {
+	if isDuplicateKey(err, "unique_customer_name") {
+		return domain.ErrCustomerNameTaken
+	}
+	if isDuplicateKey(err, "unique_customer_email") {
+		return domain.ErrCustomerEmailTaken
+	}
 	return err
 }
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+type mysqlExportRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLExportRepository(db *sql.DB) repository.ExportRepository {
+	return &mysqlExportRepository{db}
+}
+
+func (r *mysqlExportRepository) StreamTransactions(ctx context.Context, since time.Time, fn func(*domain.TransactionExport) error) error {
+	query := `
+		SELECT id, customer_id, investment_id, type, status, amount, fee, units, nab, nab_date,
+			transaction_date, settlement_date, completed_date, reverses_id, external_reference, updated_at
+		FROM transactions
+		WHERE tenant_id = ? AND updated_at >= ?
+		ORDER BY updated_at, id
+	`
+	return stream(ctx, r.db, query, since, func(rows *sql.Rows) error {
+		var row domain.TransactionExport
+		var nabDate, settlementDate, completedDate sql.NullTime
+		var reversesID, externalReference sql.NullString
+
+		if err := rows.Scan(
+			&row.ID,
+			&row.CustomerID,
+			&row.InvestmentID,
+			&row.Type,
+			&row.Status,
+			&row.Amount,
+			&row.Fee,
+			&row.Units,
+			&row.NAB,
+			&nabDate,
+			&row.TransactionDate,
+			&settlementDate,
+			&completedDate,
+			&reversesID,
+			&externalReference,
+			&row.UpdatedAt); err != nil {
+			return err
+		}
+
+		if nabDate.Valid {
+			row.NABDate = nabDate.Time.Format(utils.DateLayout)
+		}
+		row.SettlementDate = nullTimePtr(settlementDate)
+		row.CompletedDate = nullTimePtr(completedDate)
+		row.ReversesID = reversesID.String
+		row.ExternalReference = externalReference.String
+		return fn(&row)
+	})
+}
+
+func (r *mysqlExportRepository) StreamHoldings(ctx context.Context, since time.Time, fn func(*domain.HoldingExport) error) error {
+	query := `
+		SELECT ci.id, ci.customer_id, ci.investment_id, ci.units, ci.updated_at
+		FROM customer_investments ci
+		JOIN customers c ON c.id = ci.customer_id
+		WHERE c.tenant_id = ? AND ci.updated_at >= ?
+		ORDER BY ci.updated_at, ci.id
+	`
+	return stream(ctx, r.db, query, since, func(rows *sql.Rows) error {
+		var row domain.HoldingExport
+		if err := rows.Scan(&row.ID, &row.CustomerID, &row.InvestmentID, &row.Units, &row.UpdatedAt); err != nil {
+			return err
+		}
+		return fn(&row)
+	})
+}
+
+func (r *mysqlExportRepository) StreamNABHistory(ctx context.Context, since time.Time, fn func(*domain.NABExport) error) error {
+	query := `
+		SELECT n.investment_id, n.nab_date, n.nab, n.updated_at
+		FROM nab_history n
+		JOIN investments i ON i.id = n.investment_id
+		WHERE i.tenant_id = ? AND n.updated_at >= ?
+		ORDER BY n.updated_at, n.investment_id, n.nab_date
+	`
+	return stream(ctx, r.db, query, since, func(rows *sql.Rows) error {
+		var row domain.NABExport
+		var date time.Time
+		if err := rows.Scan(&row.InvestmentID, &date, &row.NAB, &row.UpdatedAt); err != nil {
+			return err
+		}
+		row.Date = date.Format(utils.DateLayout)
+		return fn(&row)
+	})
+}
+
+// stream runs query with the tenant and since, and hands each row to scan as
+// it is read
+func stream(ctx context.Context, db *sql.DB, query string, since time.Time, scan func(*sql.Rows) error) error {
+	rows, err := executor(ctx, db).QueryContext(ctx, query, tenantID(ctx), since)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
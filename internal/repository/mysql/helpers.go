@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"strings"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry is the server error number for unique key violations
+const mysqlErrDuplicateEntry = 1062
+
+// tenantID returns the tenant queries are scoped to. Customers, investments,
+// transactions and the records hanging off them are only read and written
+// within the tenant of the context; records keyed by an ID already read
+// within it, such as a holding or wallet, are updated by that ID alone.
+func tenantID(ctx context.Context) string {
+	return domain.TenantFromContext(ctx)
+}
+
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTimePtr returns nil for NULL timestamps
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// nullJSON stores empty JSON documents as NULL
+func nullJSON(data json.RawMessage) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+// isDuplicateKey reports whether err is a unique key violation on the named key
+func isDuplicateKey(err error, key string) bool {
+	var mysqlErr *mysqldriver.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrDuplicateEntry {
+		return false
+	}
+	return strings.Contains(mysqlErr.Message, "'"+key+"'") || strings.Contains(mysqlErr.Message, "."+key+"'")
+}
@@ -8,6 +8,11 @@ import (
 	"nobi-assesment/pkg/utils"
 )
 
+const investmentColumns = `id, name, description, risk_level, category, currency, manager, inception_date, status,
+	min_initial_subscription, min_subsequent_subscription, min_redemption, min_remaining_balance,
+	max_holding_per_customer, daily_subscription_limit, daily_redemption_limit,
+	total_units, total_balance, current_nab`
+
 type mysqlInvestmentRepository struct {
 	db *sql.DB
 }
@@ -17,37 +22,53 @@ func NewMySQLInvestmentRepository(db *sql.DB) repository.InvestmentRepository {
 }
 
 func (r *mysqlInvestmentRepository) Create(ctx context.Context, investment *domain.Investment) error {
-	query := "INSERT INTO investments (id, name, total_units, total_balance, current_nab) VALUES (?, ?, ?, ?, ?)"
-	_, err := r.db.ExecContext(ctx, query, investment.ID, investment.Name, investment.TotalUnits, investment.TotalBalance, investment.NAB)
+	query := `
+		INSERT INTO investments (id, tenant_id, name, description, risk_level, category, currency, manager, inception_date, status,
+			min_initial_subscription, min_subsequent_subscription, min_redemption, min_remaining_balance,
+			max_holding_per_customer, daily_subscription_limit, daily_redemption_limit,
+			total_units, total_balance, current_nab)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		investment.ID,
+		tenantID(ctx),
+		investment.Name,
+		nullString(investment.Description),
+		investment.RiskLevel,
+		nullString(string(investment.Category)),
+		investment.Currency,
+		nullString(investment.Manager),
+		nullString(investment.InceptionDate),
+		investment.Status,
+		investment.Rules.MinInitialSubscription,
+		investment.Rules.MinSubsequentSubscription,
+		investment.Rules.MinRedemption,
+		investment.Rules.MinRemainingBalance,
+		investment.Rules.MaxHoldingPerCustomer,
+		investment.Rules.DailySubscriptionLimit,
+		investment.Rules.DailyRedemptionLimit,
+		investment.TotalUnits,
+		investment.TotalBalance,
+		investment.NAB)
 	return err
 }
 
 func (r *mysqlInvestmentRepository) GetByID(ctx context.Context, id string) (*domain.Investment, error) {
-	query := "SELECT id, name, total_units, total_balance, current_nab FROM investments WHERE id = ?"
+	query := "SELECT " + investmentColumns + " FROM investments WHERE id = ? AND tenant_id = ?"
 
-	var investment domain.Investment
-	var currentNAB float64
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&investment.ID, &investment.Name, &investment.TotalUnits, &investment.TotalBalance, &currentNAB)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, err
-		}
-		return nil, err
-	}
+	return scanInvestment(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
 
-	if currentNAB <= 0 {
-		investment.NAB = utils.ValidateNAB(investment.TotalBalance, investment.TotalUnits)
-	} else {
-		investment.NAB = currentNAB
-	}
+func (r *mysqlInvestmentRepository) GetAll(ctx context.Context) ([]*domain.Investment, error) {
+	return r.getAll(ctx, "SELECT "+investmentColumns+" FROM investments WHERE tenant_id = ?")
+}
 
-	return &investment, nil
+func (r *mysqlInvestmentRepository) GetAllForUpdate(ctx context.Context) ([]*domain.Investment, error) {
+	return r.getAll(ctx, "SELECT "+investmentColumns+" FROM investments WHERE tenant_id = ? FOR UPDATE")
 }
 
-func (r *mysqlInvestmentRepository) GetAll(ctx context.Context) ([]*domain.Investment, error) {
-	query := "SELECT id, name, total_units, total_balance, current_nab FROM investments"
-	rows, err := r.db.QueryContext(ctx, query)
+func (r *mysqlInvestmentRepository) getAll(ctx context.Context, query string) ([]*domain.Investment, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -55,34 +76,109 @@ func (r *mysqlInvestmentRepository) GetAll(ctx context.Context) ([]*domain.Inves
 
 	investments := []*domain.Investment{}
 	for rows.Next() {
-		var investment domain.Investment
-		var currentNAB float64
-		if err := rows.Scan(
-			&investment.ID, &investment.Name, &investment.TotalUnits, &investment.TotalBalance, &currentNAB); err != nil {
+		investment, err := scanInvestment(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		if currentNAB <= 0 {
-			investment.NAB = utils.ValidateNAB(
-				investment.TotalBalance,
-				investment.TotalUnits,
-			)
-		} else {
-			investment.NAB = currentNAB
-		}
-
-		investments = append(investments, &investment)
+		investments = append(investments, investment)
 	}
 
 	return investments, nil
 }
 
+func (r *mysqlInvestmentRepository) Update(ctx context.Context, investment *domain.Investment) error {
+	query := `
+		UPDATE investments
+		SET name = ?, description = ?, risk_level = ?, category = ?, currency = ?, manager = ?, inception_date = ?, status = ?,
+			min_initial_subscription = ?, min_subsequent_subscription = ?, min_redemption = ?, min_remaining_balance = ?,
+			max_holding_per_customer = ?, daily_subscription_limit = ?, daily_redemption_limit = ?
+		WHERE id = ? AND tenant_id = ?
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		investment.Name,
+		nullString(investment.Description),
+		investment.RiskLevel,
+		nullString(string(investment.Category)),
+		investment.Currency,
+		nullString(investment.Manager),
+		nullString(investment.InceptionDate),
+		investment.Status,
+		investment.Rules.MinInitialSubscription,
+		investment.Rules.MinSubsequentSubscription,
+		investment.Rules.MinRedemption,
+		investment.Rules.MinRemainingBalance,
+		investment.Rules.MaxHoldingPerCustomer,
+		investment.Rules.DailySubscriptionLimit,
+		investment.Rules.DailyRedemptionLimit,
+		investment.ID,
+		tenantID(ctx))
+	return err
+}
+
 func (r *mysqlInvestmentRepository) UpdateBalance(ctx context.Context, id string, amountChange float64, unitsChange float64) error {
 	query := `
-		UPDATE investments 
-		SET total_balance = total_balance + ?, total_units = total_units + ? 
-		WHERE id = ?
+		UPDATE investments
+		SET total_balance = total_balance + ?, total_units = total_units + ?
+		WHERE id = ? AND tenant_id = ?
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, amountChange, unitsChange, id, tenantID(ctx))
+	return err
+}
+
+func (r *mysqlInvestmentRepository) RecordNAB(ctx context.Context, id, date string, nab float64) error {
+	query := `
+		INSERT INTO nab_history (investment_id, nab_date, nab)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE nab = VALUES(nab)
 	`
-	_, err := r.db.ExecContext(ctx, query, amountChange, unitsChange, id)
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, id, date, nab)
 	return err
 }
+
+// scanInvestment reads a row selected with investmentColumns
+func scanInvestment(row interface{ Scan(...any) error }) (*domain.Investment, error) {
+	var investment domain.Investment
+	var description, category, manager sql.NullString
+	var inceptionDate sql.NullTime
+	var currentNAB float64
+
+	err := row.Scan(
+		&investment.ID,
+		&investment.Name,
+		&description,
+		&investment.RiskLevel,
+		&category,
+		&investment.Currency,
+		&manager,
+		&inceptionDate,
+		&investment.Status,
+		&investment.Rules.MinInitialSubscription,
+		&investment.Rules.MinSubsequentSubscription,
+		&investment.Rules.MinRedemption,
+		&investment.Rules.MinRemainingBalance,
+		&investment.Rules.MaxHoldingPerCustomer,
+		&investment.Rules.DailySubscriptionLimit,
+		&investment.Rules.DailyRedemptionLimit,
+		&investment.TotalUnits,
+		&investment.TotalBalance,
+		&currentNAB)
+	if err != nil {
+		return nil, err
+	}
+
+	investment.Description = description.String
+	investment.Category = domain.FundCategory(category.String)
+	investment.Manager = manager.String
+	if inceptionDate.Valid {
+		investment.InceptionDate = inceptionDate.Time.Format(utils.DateLayout)
+	}
+
+	if currentNAB <= 0 {
+		investment.NAB = utils.ValidateNAB(investment.TotalBalance, investment.TotalUnits)
+	} else {
+		investment.NAB = currentNAB
+	}
+
+	return &investment, nil
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"strings"
+)
+
+type mysqlLedgerRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLLedgerRepository(db *sql.DB) repository.LedgerRepository {
+	return &mysqlLedgerRepository{db}
+}
+
+func (r *mysqlLedgerRepository) Post(ctx context.Context, entry *domain.JournalEntry) error {
+	query := "INSERT INTO journal_entries (id, tenant_id, type, transaction_id, reference, created_at) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		entry.ID,
+		tenantID(ctx),
+		entry.Type,
+		nullString(entry.TransactionID),
+		nullString(entry.Reference),
+		entry.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	placeholders := make([]string, 0, len(entry.Lines))
+	args := make([]any, 0, len(entry.Lines)*6)
+	for _, line := range entry.Lines {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?)")
+		args = append(args,
+			entry.ID,
+			line.Type,
+			nullString(line.CustomerID),
+			nullString(line.InvestmentID),
+			line.Asset,
+			line.Amount)
+	}
+
+	query = "INSERT INTO journal_lines (entry_id, account_type, customer_id, investment_id, asset, amount) VALUES " +
+		strings.Join(placeholders, ", ")
+	_, err = executor(ctx, r.db).ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *mysqlLedgerRepository) GetEntries(ctx context.Context, transactionID, customerID string) ([]*domain.JournalEntry, error) {
+	query := `
+		SELECT e.id, e.type, e.transaction_id, e.reference, e.created_at,
+			l.account_type, l.customer_id, l.investment_id, l.asset, l.amount
+		FROM journal_entries e
+		JOIN journal_lines l ON l.entry_id = e.id
+		WHERE e.tenant_id = ?
+	`
+	args := []any{tenantID(ctx)}
+	if transactionID != "" {
+		query += " AND e.transaction_id = ?"
+		args = append(args, transactionID)
+	}
+	if customerID != "" {
+		query += " AND e.id IN (SELECT entry_id FROM journal_lines WHERE customer_id = ?)"
+		args = append(args, customerID)
+	}
+	query += " ORDER BY e.created_at DESC, e.id, l.id"
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.JournalEntry{}
+	var current *domain.JournalEntry
+	for rows.Next() {
+		var entry domain.JournalEntry
+		var entryTransactionID, reference, lineCustomerID, lineInvestmentID sql.NullString
+		var line domain.JournalLine
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.Type,
+			&entryTransactionID,
+			&reference,
+			&entry.CreatedAt,
+			&line.Type,
+			&lineCustomerID,
+			&lineInvestmentID,
+			&line.Asset,
+			&line.Amount); err != nil {
+			return nil, err
+		}
+		line.CustomerID = lineCustomerID.String
+		line.InvestmentID = lineInvestmentID.String
+
+		// Lines of an entry are adjacent, start a new entry when the ID changes
+		if current == nil || current.ID != entry.ID {
+			entry.TransactionID = entryTransactionID.String
+			entry.Reference = reference.String
+			current = &entry
+			entries = append(entries, current)
+		}
+		current.Lines = append(current.Lines, line)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *mysqlLedgerRepository) GetBalances(ctx context.Context, entryType domain.JournalEntryType) ([]*domain.AccountBalance, error) {
+	query := `
+		SELECT l.account_type, l.customer_id, l.investment_id, l.asset, SUM(l.amount)
+		FROM journal_lines l
+		JOIN journal_entries e ON e.id = l.entry_id
+		WHERE e.tenant_id = ?
+	`
+	args := []any{tenantID(ctx)}
+	if entryType != "" {
+		query += " AND e.type = ?"
+		args = append(args, entryType)
+	}
+	query += " GROUP BY l.account_type, l.customer_id, l.investment_id, l.asset"
+
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := []*domain.AccountBalance{}
+	for rows.Next() {
+		var balance domain.AccountBalance
+		var customerID, investmentID sql.NullString
+
+		if err := rows.Scan(
+			&balance.Type,
+			&customerID,
+			&investmentID,
+			&balance.Asset,
+			&balance.Balance); err != nil {
+			return nil, err
+		}
+		balance.CustomerID = customerID.String
+		balance.InvestmentID = investmentID.String
+
+		balances = append(balances, &balance)
+	}
+
+	return balances, rows.Err()
+}
@@ -0,0 +1,256 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+const recurringPlanColumns = `p.id, p.customer_id, p.investment_id, p.amount, p.frequency, p.start_date, p.end_date,
+	p.next_run_date, p.status, p.risk_acknowledged, p.attempts, p.retry_at, p.last_run_at, p.last_error, p.locked_until,
+	p.created_at, p.updated_at`
+
+// recurringPlanTables joins the customer, plans belong to the tenant of their customer
+const recurringPlanTables = "recurring_plans p JOIN customers c ON c.id = p.customer_id"
+
+type mysqlRecurringPlanRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLRecurringPlanRepository(db *sql.DB) repository.RecurringPlanRepository {
+	return &mysqlRecurringPlanRepository{db}
+}
+
+func (r *mysqlRecurringPlanRepository) Create(ctx context.Context, plan *domain.RecurringPlan) error {
+	query := `
+		INSERT INTO recurring_plans (id, customer_id, investment_id, amount, frequency, start_date, end_date, next_run_date,
+			status, risk_acknowledged)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		plan.ID,
+		plan.CustomerID,
+		plan.InvestmentID,
+		plan.Amount,
+		plan.Frequency,
+		plan.StartDate,
+		nullString(plan.EndDate),
+		nullString(plan.NextRunDate),
+		plan.Status,
+		plan.RiskAcknowledged)
+	return err
+}
+
+func (r *mysqlRecurringPlanRepository) GetByID(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	query := "SELECT " + recurringPlanColumns + " FROM " + recurringPlanTables + " WHERE p.id = ? AND c.tenant_id = ?"
+
+	return scanRecurringPlan(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
+
+func (r *mysqlRecurringPlanRepository) GetForUpdate(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	query := "SELECT " + recurringPlanColumns + " FROM " + recurringPlanTables + " WHERE p.id = ? AND c.tenant_id = ? FOR UPDATE"
+
+	return scanRecurringPlan(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
+
+func (r *mysqlRecurringPlanRepository) GetAll(ctx context.Context, customerID string) ([]*domain.RecurringPlan, error) {
+	query := "SELECT " + recurringPlanColumns + " FROM " + recurringPlanTables + " WHERE c.tenant_id = ?"
+	args := []any{tenantID(ctx)}
+	if customerID != "" {
+		query += " AND p.customer_id = ?"
+		args = append(args, customerID)
+	}
+	query += " ORDER BY p.created_at DESC"
+
+	return r.queryPlans(ctx, query, args...)
+}
+
+func (r *mysqlRecurringPlanRepository) GetDue(ctx context.Context, today string, now time.Time, limit int) ([]*domain.RecurringPlan, error) {
+	query := "SELECT " + recurringPlanColumns + " FROM " + recurringPlanTables + `
+		WHERE c.tenant_id = ? AND p.status = ? AND p.next_run_date <= ? AND (p.retry_at IS NULL OR p.retry_at <= ?)
+			AND (p.locked_until IS NULL OR p.locked_until < ?)
+		ORDER BY p.next_run_date
+		LIMIT ?`
+
+	return r.queryPlans(ctx, query, tenantID(ctx), domain.PlanActive, today, now, now, limit)
+}
+
+func (r *mysqlRecurringPlanRepository) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	query := `
+		UPDATE recurring_plans SET locked_until = ?
+		WHERE id = ? AND status = ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, until, id, domain.PlanActive, now)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *mysqlRecurringPlanRepository) Update(ctx context.Context, plan *domain.RecurringPlan) error {
+	query := "UPDATE recurring_plans SET " + recurringPlanAssignments + " WHERE id = ?"
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, append(recurringPlanValues(plan), plan.ID)...)
+	return err
+}
+
+func (r *mysqlRecurringPlanRepository) Release(ctx context.Context, plan *domain.RecurringPlan, claimedUntil time.Time) (bool, error) {
+	query := "UPDATE recurring_plans SET " + recurringPlanAssignments + ", locked_until = NULL WHERE id = ? AND locked_until = ?"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, append(recurringPlanValues(plan), plan.ID, claimedUntil)...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// recurringPlanAssignments sets the columns a plan changes, to the values of
+// recurringPlanValues
+const recurringPlanAssignments = `amount = ?, frequency = ?, end_date = ?, next_run_date = ?, status = ?, attempts = ?,
+	retry_at = ?, last_run_at = ?, last_error = ?`
+
+func recurringPlanValues(plan *domain.RecurringPlan) []any {
+	return []any{
+		plan.Amount,
+		plan.Frequency,
+		nullString(plan.EndDate),
+		nullString(plan.NextRunDate),
+		plan.Status,
+		plan.Attempts,
+		plan.RetryAt,
+		plan.LastRunAt,
+		nullString(plan.LastError),
+	}
+}
+
+func (r *mysqlRecurringPlanRepository) CreateRun(ctx context.Context, run *domain.RecurringPlanRun) error {
+	query := `
+		INSERT INTO recurring_plan_runs (id, plan_id, scheduled_for, status, attempts, transaction_id, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		run.ID,
+		run.PlanID,
+		run.ScheduledFor,
+		run.Status,
+		run.Attempts,
+		nullString(run.TransactionID),
+		nullString(run.Error))
+	return err
+}
+
+func (r *mysqlRecurringPlanRepository) GetRuns(ctx context.Context, planID string) ([]*domain.RecurringPlanRun, error) {
+	query := `
+		SELECT r.id, r.plan_id, r.scheduled_for, r.status, r.attempts, r.transaction_id, r.error, r.created_at
+		FROM recurring_plan_runs r
+		JOIN recurring_plans p ON p.id = r.plan_id
+		JOIN customers c ON c.id = p.customer_id
+		WHERE r.plan_id = ? AND c.tenant_id = ?
+		ORDER BY r.scheduled_for DESC
+	`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, planID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []*domain.RecurringPlanRun{}
+	for rows.Next() {
+		var run domain.RecurringPlanRun
+		var scheduledFor time.Time
+		var transactionID, runError sql.NullString
+
+		if err := rows.Scan(
+			&run.ID,
+			&run.PlanID,
+			&scheduledFor,
+			&run.Status,
+			&run.Attempts,
+			&transactionID,
+			&runError,
+			&run.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		run.ScheduledFor = scheduledFor.Format(utils.DateLayout)
+		run.TransactionID = transactionID.String
+		run.Error = runError.String
+		runs = append(runs, &run)
+	}
+
+	return runs, rows.Err()
+}
+
+func (r *mysqlRecurringPlanRepository) queryPlans(ctx context.Context, query string, args ...any) ([]*domain.RecurringPlan, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []*domain.RecurringPlan{}
+	for rows.Next() {
+		plan, err := scanRecurringPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+
+	return plans, rows.Err()
+}
+
+// scanRecurringPlan reads a row selected with recurringPlanColumns
+func scanRecurringPlan(row interface{ Scan(...any) error }) (*domain.RecurringPlan, error) {
+	var plan domain.RecurringPlan
+	var startDate time.Time
+	var endDate, nextRunDate, retryAt, lastRunAt, lockedUntil sql.NullTime
+	var lastError sql.NullString
+
+	err := row.Scan(
+		&plan.ID,
+		&plan.CustomerID,
+		&plan.InvestmentID,
+		&plan.Amount,
+		&plan.Frequency,
+		&startDate,
+		&endDate,
+		&nextRunDate,
+		&plan.Status,
+		&plan.RiskAcknowledged,
+		&plan.Attempts,
+		&retryAt,
+		&lastRunAt,
+		&lastError,
+		&lockedUntil,
+		&plan.CreatedAt,
+		&plan.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.StartDate = startDate.Format(utils.DateLayout)
+	if endDate.Valid {
+		plan.EndDate = endDate.Time.Format(utils.DateLayout)
+	}
+	if nextRunDate.Valid {
+		plan.NextRunDate = nextRunDate.Time.Format(utils.DateLayout)
+	}
+	if retryAt.Valid {
+		plan.RetryAt = &retryAt.Time
+	}
+	if lastRunAt.Valid {
+		plan.LastRunAt = &lastRunAt.Time
+	}
+	plan.LastError = lastError.String
+	if lockedUntil.Valid {
+		plan.LockedUntil = &lockedUntil.Time
+	}
+
+	return &plan, nil
+}
@@ -0,0 +1,69 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+)
+
+type mysqlRiskProfileRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLRiskProfileRepository(db *sql.DB) repository.RiskProfileRepository {
+	return &mysqlRiskProfileRepository{db}
+}
+
+func (r *mysqlRiskProfileRepository) Create(ctx context.Context, profile *domain.CustomerRiskProfile) error {
+	answers, err := json.Marshal(profile.Answers)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO customer_risk_profiles (id, customer_id, profile, score, answers, assessed_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
+		profile.ID,
+		profile.CustomerID,
+		profile.Profile,
+		profile.Score,
+		string(answers),
+		profile.AssessedAt,
+		profile.ExpiresAt)
+	return err
+}
+
+func (r *mysqlRiskProfileRepository) GetLatestByCustomer(ctx context.Context, customerID string) (*domain.CustomerRiskProfile, error) {
+	query := `
+		SELECT p.id, p.customer_id, p.profile, p.score, p.answers, p.assessed_at, p.expires_at
+		FROM customer_risk_profiles p
+		JOIN customers c ON c.id = p.customer_id
+		WHERE p.customer_id = ? AND c.tenant_id = ?
+		ORDER BY p.assessed_at DESC
+		LIMIT 1
+	`
+
+	var profile domain.CustomerRiskProfile
+	var answers []byte
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, customerID, tenantID(ctx)).Scan(
+		&profile.ID,
+		&profile.CustomerID,
+		&profile.Profile,
+		&profile.Score,
+		&answers,
+		&profile.AssessedAt,
+		&profile.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(answers, &profile.Answers); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
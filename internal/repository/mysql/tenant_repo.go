@@ -0,0 +1,139 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+)
+
+const tenantColumns = `id, name, host, subscription_fee_rate, redemption_fee_rate, cut_off_time, enabled_investment_ids,
+	created_at, updated_at`
+
+type mysqlTenantRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLTenantRepository(db *sql.DB) repository.TenantRepository {
+	return &mysqlTenantRepository{db}
+}
+
+func (r *mysqlTenantRepository) Create(ctx context.Context, tenant *domain.Tenant) error {
+	investmentIDs, err := json.Marshal(tenant.Config.EnabledInvestmentIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO tenants (id, name, host, subscription_fee_rate, redemption_fee_rate, cut_off_time, enabled_investment_ids)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
+		tenant.ID,
+		tenant.Name,
+		nullString(tenant.Host),
+		tenant.Config.SubscriptionFeeRate,
+		tenant.Config.RedemptionFeeRate,
+		nullString(tenant.Config.CutOffTime),
+		string(investmentIDs))
+	return translateTenantError(err)
+}
+
+func (r *mysqlTenantRepository) GetByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	query := "SELECT " + tenantColumns + " FROM tenants WHERE id = ?"
+
+	return scanTenant(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+}
+
+func (r *mysqlTenantRepository) GetByHost(ctx context.Context, host string) (*domain.Tenant, error) {
+	query := "SELECT " + tenantColumns + " FROM tenants WHERE host = ?"
+
+	return scanTenant(executor(ctx, r.db).QueryRowContext(ctx, query, host))
+}
+
+func (r *mysqlTenantRepository) GetAll(ctx context.Context) ([]*domain.Tenant, error) {
+	query := "SELECT " + tenantColumns + " FROM tenants ORDER BY id"
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tenants := []*domain.Tenant{}
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, rows.Err()
+}
+
+func (r *mysqlTenantRepository) Update(ctx context.Context, tenant *domain.Tenant) error {
+	investmentIDs, err := json.Marshal(tenant.Config.EnabledInvestmentIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE tenants
+		SET name = ?, host = ?, subscription_fee_rate = ?, redemption_fee_rate = ?, cut_off_time = ?, enabled_investment_ids = ?
+		WHERE id = ?
+	`
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
+		tenant.Name,
+		nullString(tenant.Host),
+		tenant.Config.SubscriptionFeeRate,
+		tenant.Config.RedemptionFeeRate,
+		nullString(tenant.Config.CutOffTime),
+		string(investmentIDs),
+		tenant.ID)
+	return translateTenantError(err)
+}
+
+// scanTenant reads a row selected with tenantColumns
+func scanTenant(row interface{ Scan(...any) error }) (*domain.Tenant, error) {
+	var tenant domain.Tenant
+	var host, cutOffTime sql.NullString
+	var subscriptionFeeRate, redemptionFeeRate sql.NullFloat64
+	var investmentIDs []byte
+
+	err := row.Scan(
+		&tenant.ID,
+		&tenant.Name,
+		&host,
+		&subscriptionFeeRate,
+		&redemptionFeeRate,
+		&cutOffTime,
+		&investmentIDs,
+		&tenant.CreatedAt,
+		&tenant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(investmentIDs, &tenant.Config.EnabledInvestmentIDs); err != nil {
+		return nil, err
+	}
+	tenant.Host = host.String
+	tenant.Config.CutOffTime = cutOffTime.String
+	if subscriptionFeeRate.Valid {
+		tenant.Config.SubscriptionFeeRate = &subscriptionFeeRate.Float64
+	}
+	if redemptionFeeRate.Valid {
+		tenant.Config.RedemptionFeeRate = &redemptionFeeRate.Float64
+	}
+
+	return &tenant, nil
+}
+
+// translateTenantError maps duplicate IDs and hosts to a domain error
+func translateTenantError(err error) error {
+	if isDuplicateKey(err, "PRIMARY") || isDuplicateKey(err, "unique_tenant_host") {
+		return domain.ErrTenantTaken
+	}
+	return err
+}
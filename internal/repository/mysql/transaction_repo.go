@@ -5,8 +5,16 @@ import (
 	"database/sql"
 	"nobi-assesment/internal/domain"
 	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
 )
 
+const transactionColumns = `t.id, t.customer_id, t.investment_id, i.name, t.type, t.status, t.amount, t.fee, t.units,
+	t.nab, t.nab_date, t.balance_before, t.balance_after, t.risk_acknowledged,
+	t.transaction_date, t.settlement_date, t.completed_date, t.reverses_id, t.notes, t.external_reference`
+
+const transactionTables = "transactions t JOIN investments i ON t.investment_id = i.id"
+
 type mysqlTransactionRepository struct {
 	db *sql.DB
 }
@@ -17,29 +25,127 @@ func NewMySQLTransactionRepository(db *sql.DB) repository.TransactionRepository
 
 func (r *mysqlTransactionRepository) Create(ctx context.Context, transaction *domain.Transaction) error {
 	query := `
-		INSERT INTO transactions (id, customer_id, investment_id, type, amount, units, nab) 
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO transactions (id, tenant_id, customer_id, investment_id, type, status, amount, fee, units,
+			nab, nab_date, balance_before, balance_after, risk_acknowledged,
+			transaction_date, settlement_date, completed_date, reverses_id, notes, external_reference)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
 		transaction.ID,
+		tenantID(ctx),
 		transaction.CustomerID,
 		transaction.InvestmentID,
 		transaction.Type,
+		transaction.Status,
 		transaction.Amount,
+		transaction.Fee,
 		transaction.Units,
-		transaction.NAB)
+		transaction.NAB,
+		nullString(transaction.NABDate),
+		transaction.BalanceBefore,
+		transaction.BalanceAfter,
+		transaction.RiskAcknowledged,
+		transaction.TransactionDate,
+		transaction.SettlementDate,
+		transaction.CompletedDate,
+		nullString(transaction.ReversesID),
+		nullString(transaction.Reason),
+		nullString(transaction.ExternalReference))
+	if isDuplicateKey(err, "unique_reverses_id") {
+		return domain.ErrTransactionAlreadyReversed
+	}
+	if isDuplicateKey(err, "unique_external_reference") {
+		return domain.ErrDuplicateExternalReference
+	}
 	return err
 }
 
+func (r *mysqlTransactionRepository) GetByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM " + transactionTables + " WHERE t.id = ? AND t.tenant_id = ?"
+
+	return scanTransaction(executor(ctx, r.db).QueryRowContext(ctx, query, id, tenantID(ctx)))
+}
+
+func (r *mysqlTransactionRepository) GetByExternalReference(ctx context.Context, reference string) (*domain.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM " + transactionTables + " WHERE t.external_reference = ? AND t.tenant_id = ?"
+
+	return scanTransaction(executor(ctx, r.db).QueryRowContext(ctx, query, reference, tenantID(ctx)))
+}
+
 func (r *mysqlTransactionRepository) GetByCustomerID(ctx context.Context, customerID string) ([]*domain.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM " + transactionTables + `
+		WHERE t.customer_id = ? AND t.tenant_id = ?
+		ORDER BY t.transaction_date DESC`
+
+	return r.queryTransactions(ctx, query, customerID, tenantID(ctx))
+}
+
+func (r *mysqlTransactionRepository) GetHistory(ctx context.Context, customerID string, before time.Time) ([]*domain.Transaction, error) {
+	query := "SELECT " + transactionColumns + " FROM " + transactionTables + `
+		WHERE t.customer_id = ? AND t.tenant_id = ? AND t.transaction_date < ?
+		ORDER BY t.transaction_date, t.id`
+
+	return r.queryTransactions(ctx, query, customerID, tenantID(ctx), before)
+}
+
+func (r *mysqlTransactionRepository) GetLatestNAB(ctx context.Context, investmentID string, before time.Time) (float64, time.Time, error) {
 	query := `
-		SELECT t.id, t.customer_id, t.investment_id, i.name, t.type, t.amount, t.units, t.nab, t.transaction_date
-		FROM transactions t
-		JOIN investments i ON t.investment_id = i.id
-		WHERE t.customer_id = ?
-		ORDER BY t.transaction_date DESC
+		SELECT nab, transaction_date
+		FROM transactions
+		WHERE investment_id = ? AND tenant_id = ? AND type IN ('DEPOSIT', 'WITHDRAW') AND status = 'COMPLETED'
+			AND transaction_date < ?
+		ORDER BY transaction_date DESC
+		LIMIT 1
 	`
-	rows, err := r.db.QueryContext(ctx, query, customerID)
+
+	var nab float64
+	var at time.Time
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, investmentID, tenantID(ctx), before).Scan(&nab, &at)
+	return nab, at, err
+}
+
+func (r *mysqlTransactionRepository) queryTransactions(ctx context.Context, query string, args ...any) ([]*domain.Transaction, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transactions := []*domain.Transaction{}
+	for rows.Next() {
+		transaction, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, rows.Err()
+}
+
+func (r *mysqlTransactionRepository) SumAmountSince(ctx context.Context, customerID, investmentID, transactionType string, since time.Time) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM transactions
+		WHERE customer_id = ? AND investment_id = ? AND tenant_id = ? AND type = ? AND transaction_date >= ?
+			AND status IN ('PENDING', 'COMPLETED')
+	`
+
+	var total float64
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, customerID, investmentID, tenantID(ctx), transactionType, since).Scan(&total)
+	return total, err
+}
+
+func (r *mysqlTransactionRepository) GetDueSettlements(ctx context.Context, now time.Time, limit int) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, customer_id, investment_id, type, status, amount, fee, units, nab, transaction_date, settlement_date
+		FROM transactions
+		WHERE tenant_id = ? AND type = 'WITHDRAW' AND status = 'PENDING' AND settlement_date <= ?
+		ORDER BY settlement_date
+		LIMIT ?
+	`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx), now, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -48,23 +154,123 @@ func (r *mysqlTransactionRepository) GetByCustomerID(ctx context.Context, custom
 	transactions := []*domain.Transaction{}
 	for rows.Next() {
 		var transaction domain.Transaction
-		var investmentName string
+		var settlementDate sql.NullTime
 
 		if err := rows.Scan(
 			&transaction.ID,
 			&transaction.CustomerID,
 			&transaction.InvestmentID,
-			&investmentName,
 			&transaction.Type,
+			&transaction.Status,
 			&transaction.Amount,
+			&transaction.Fee,
 			&transaction.Units,
 			&transaction.NAB,
-			&transaction.TransactionDate); err != nil {
+			&transaction.TransactionDate,
+			&settlementDate); err != nil {
 			return nil, err
 		}
+		transaction.SettlementDate = nullTimePtr(settlementDate)
 
 		transactions = append(transactions, &transaction)
 	}
 
-	return transactions, nil
+	return transactions, rows.Err()
+}
+
+func (r *mysqlTransactionRepository) GetPositions(ctx context.Context) ([]*domain.TransactionPosition, error) {
+	query := `
+		SELECT customer_id, investment_id,
+			SUM(CASE WHEN type = 'DEPOSIT' THEN units ELSE -units END),
+			SUM(CASE WHEN type = 'DEPOSIT' THEN amount - fee ELSE -amount END)
+		FROM transactions
+		WHERE tenant_id = ? AND type IN ('DEPOSIT', 'WITHDRAW') AND status IN ('PENDING', 'COMPLETED')
+		GROUP BY customer_id, investment_id
+	`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	positions := []*domain.TransactionPosition{}
+	for rows.Next() {
+		var position domain.TransactionPosition
+		if err := rows.Scan(
+			&position.CustomerID,
+			&position.InvestmentID,
+			&position.Units,
+			&position.NetCash); err != nil {
+			return nil, err
+		}
+
+		positions = append(positions, &position)
+	}
+
+	return positions, rows.Err()
+}
+
+func (r *mysqlTransactionRepository) MarkCompleted(ctx context.Context, id string, completedAt time.Time) (bool, error) {
+	query := "UPDATE transactions SET status = 'COMPLETED', completed_date = ? WHERE id = ? AND tenant_id = ? AND status = 'PENDING'"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, completedAt, id, tenantID(ctx))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+func (r *mysqlTransactionRepository) MarkReversed(ctx context.Context, id, fromStatus string) (bool, error) {
+	query := "UPDATE transactions SET status = 'REVERSED' WHERE id = ? AND tenant_id = ? AND status = ?"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, id, tenantID(ctx), fromStatus)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected == 1, err
+}
+
+// scanTransaction reads a row selected with transactionColumns
+func scanTransaction(row interface{ Scan(...any) error }) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	var nabDate, settlementDate, completedDate sql.NullTime
+	var reversesID, notes, externalReference sql.NullString
+
+	err := row.Scan(
+		&transaction.ID,
+		&transaction.CustomerID,
+		&transaction.InvestmentID,
+		&transaction.InvestmentName,
+		&transaction.Type,
+		&transaction.Status,
+		&transaction.Amount,
+		&transaction.Fee,
+		&transaction.Units,
+		&transaction.NAB,
+		&nabDate,
+		&transaction.BalanceBefore,
+		&transaction.BalanceAfter,
+		&transaction.RiskAcknowledged,
+		&transaction.TransactionDate,
+		&settlementDate,
+		&completedDate,
+		&reversesID,
+		&notes,
+		&externalReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if nabDate.Valid {
+		transaction.NABDate = nabDate.Time.Format(utils.DateLayout)
+	}
+	transaction.SettlementDate = nullTimePtr(settlementDate)
+	transaction.CompletedDate = nullTimePtr(completedDate)
+	transaction.ReversesID = reversesID.String
+	transaction.Reason = notes.String
+	transaction.ExternalReference = externalReference.String
+
+	return &transaction, nil
 }
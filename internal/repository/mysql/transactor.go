@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"nobi-assesment/internal/repository"
+)
+
+type txKey struct{}
+
+// dbExecutor is the subset of *sql.DB and *sql.Tx used by the repositories
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executor returns the transaction carried by ctx, or db when there is none,
+// so repository calls made inside WithinTransaction join the transaction.
+func executor(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+type mysqlTransactor struct {
+	db *sql.DB
+}
+
+func NewMySQLTransactor(db *sql.DB) repository.Transactor {
+	return &mysqlTransactor{db}
+}
+
+func (t *mysqlTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Nested calls join the outer transaction
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once committed
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		slog.DebugContext(ctx, "transaction rolled back", "error", err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.ErrorContext(ctx, "transaction commit failed", "error", err)
+		return err
+	}
+	return nil
+}
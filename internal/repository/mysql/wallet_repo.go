@@ -0,0 +1,138 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+)
+
+const walletColumns = "w.customer_id, w.balance, w.pending_balance, w.updated_at"
+
+// walletTables joins the owner, wallets belong to the tenant of their customer
+const walletTables = "customer_wallets w JOIN customers c ON c.id = w.customer_id"
+
+type mysqlWalletRepository struct {
+	db *sql.DB
+}
+
+func NewMySQLWalletRepository(db *sql.DB) repository.WalletRepository {
+	return &mysqlWalletRepository{db}
+}
+
+func (r *mysqlWalletRepository) GetByCustomerID(ctx context.Context, customerID string) (*domain.Wallet, error) {
+	query := "SELECT " + walletColumns + " FROM " + walletTables + " WHERE w.customer_id = ? AND c.tenant_id = ?"
+
+	return scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, customerID, tenantID(ctx)))
+}
+
+func (r *mysqlWalletRepository) GetAll(ctx context.Context) ([]*domain.Wallet, error) {
+	query := "SELECT " + walletColumns + " FROM " + walletTables + " WHERE c.tenant_id = ?"
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wallets := []*domain.Wallet{}
+	for rows.Next() {
+		wallet, err := scanWallet(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		wallets = append(wallets, wallet)
+	}
+
+	return wallets, rows.Err()
+}
+
+func (r *mysqlWalletRepository) GetForUpdate(ctx context.Context, customerID string) (*domain.Wallet, error) {
+	// Wallets are only opened for customers of the request's tenant
+	query := "INSERT IGNORE INTO customer_wallets (customer_id) SELECT id FROM customers WHERE id = ? AND tenant_id = ?"
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, customerID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	query = "SELECT " + walletColumns + " FROM " + walletTables + " WHERE w.customer_id = ? AND c.tenant_id = ? FOR UPDATE"
+
+	return scanWallet(executor(ctx, r.db).QueryRowContext(ctx, query, customerID, tenantID(ctx)))
+}
+
+func (r *mysqlWalletRepository) AdjustBalance(ctx context.Context, customerID string, balanceChange, pendingChange float64) error {
+	query := `
+		UPDATE customer_wallets
+		SET balance = balance + ?, pending_balance = pending_balance + ?
+		WHERE customer_id = ?
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, balanceChange, pendingChange, customerID)
+	return err
+}
+
+func (r *mysqlWalletRepository) CreateMovement(ctx context.Context, movement *domain.WalletMovement) error {
+	query := `
+		INSERT INTO wallet_movements (id, customer_id, type, amount, balance_after, transaction_id, reference, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		movement.ID,
+		movement.CustomerID,
+		movement.Type,
+		movement.Amount,
+		movement.BalanceAfter,
+		nullString(movement.TransactionID),
+		nullString(movement.Reference),
+		movement.CreatedAt)
+	return err
+}
+
+func (r *mysqlWalletRepository) GetMovements(ctx context.Context, customerID string) ([]*domain.WalletMovement, error) {
+	query := `
+		SELECT m.id, m.customer_id, m.type, m.amount, m.balance_after, m.transaction_id, m.reference, m.created_at
+		FROM wallet_movements m
+		JOIN customers c ON c.id = m.customer_id
+		WHERE m.customer_id = ? AND c.tenant_id = ?
+		ORDER BY m.created_at DESC
+	`
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, customerID, tenantID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []*domain.WalletMovement{}
+	for rows.Next() {
+		var movement domain.WalletMovement
+		var transactionID, reference sql.NullString
+
+		if err := rows.Scan(
+			&movement.ID,
+			&movement.CustomerID,
+			&movement.Type,
+			&movement.Amount,
+			&movement.BalanceAfter,
+			&transactionID,
+			&reference,
+			&movement.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		movement.TransactionID = transactionID.String
+		movement.Reference = reference.String
+		movements = append(movements, &movement)
+	}
+
+	return movements, rows.Err()
+}
+
+func scanWallet(row interface{ Scan(...any) error }) (*domain.Wallet, error) {
+	var wallet domain.Wallet
+
+	err := row.Scan(&wallet.CustomerID, &wallet.Balance, &wallet.PendingBalance, &wallet.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wallet, nil
+}
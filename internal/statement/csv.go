@@ -0,0 +1,83 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+// writeCSV writes the statement as consecutive sections, each starting with
+// its title row and column header row and separated by an empty row
+func writeCSV(w io.Writer, statement *domain.Statement) error {
+	writer := csv.NewWriter(w)
+
+	records := [][]string{
+		{"Statement"},
+		{"Customer ID", "Name", "Email", "From", "To", "Generated at"},
+		{statement.CustomerID, statement.CustomerName, statement.CustomerEmail, statement.From, statement.To, statement.GeneratedAt.Format(time.RFC3339)},
+		{},
+		{"Opening holdings"},
+	}
+	records = append(records, holdingRecords(statement.OpeningHoldings)...)
+	records = append(records,
+		[]string{},
+		[]string{"Transactions"},
+		[]string{"Date", "Transaction ID", "Investment", "Type", "Status", "Amount", "Fee", "Units", "NAB"},
+	)
+	for _, transaction := range statement.Transactions {
+		records = append(records, []string{
+			transaction.TransactionDate.Format(utils.DateLayout),
+			transaction.ID,
+			transaction.InvestmentName,
+			transaction.Type,
+			transaction.Status,
+			formatAmount(transaction.Amount),
+			formatAmount(transaction.Fee),
+			formatUnits(transaction.Units),
+			formatUnits(transaction.NAB),
+		})
+	}
+	records = append(records, []string{}, []string{"Closing holdings"})
+	records = append(records, holdingRecords(statement.ClosingHoldings)...)
+	records = append(records,
+		[]string{},
+		[]string{"Realized gains"},
+		[]string{"Date", "Transaction ID", "Investment", "Units", "Proceeds", "Cost basis", "Gain"},
+	)
+	for _, gain := range statement.RealizedGains {
+		records = append(records, []string{
+			gain.Date.Format(utils.DateLayout),
+			gain.TransactionID,
+			gain.InvestmentName,
+			formatUnits(gain.Units),
+			formatAmount(gain.Proceeds),
+			formatAmount(gain.CostBasis),
+			formatAmount(gain.Gain),
+		})
+	}
+	records = append(records,
+		[]string{},
+		[]string{"Summary"},
+		[]string{"Opening value", "Closing value", "Realized gain"},
+		[]string{formatAmount(statement.OpeningValue), formatAmount(statement.ClosingValue), formatAmount(statement.TotalRealizedGain)},
+	)
+
+	return writer.WriteAll(records)
+}
+
+func holdingRecords(holdings []*domain.StatementHolding) [][]string {
+	records := [][]string{{"Investment ID", "Investment", "Units", "NAB", "NAB date", "Value"}}
+	for _, holding := range holdings {
+		records = append(records, []string{
+			holding.InvestmentID,
+			holding.InvestmentName,
+			formatUnits(holding.Units),
+			formatUnits(holding.NAB),
+			holding.NABDate,
+			formatAmount(holding.Value),
+		})
+	}
+	return records
+}
@@ -0,0 +1,146 @@
+package statement
+
+import (
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/pkg/utils"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const pdfRowHeight = 6
+
+// pdfColumn is a table column, right aligned when it holds numbers
+type pdfColumn struct {
+	title   string
+	width   float64
+	numeric bool
+}
+
+var (
+	holdingColumns = []pdfColumn{
+		{"Investment ID", 70, false}, {"Investment", 75, false}, {"Units", 30, true},
+		{"NAB", 30, true}, {"NAB date", 32, false}, {"Value", 40, true},
+	}
+	transactionColumns = []pdfColumn{
+		{"Date", 22, false}, {"Transaction ID", 62, false}, {"Investment", 55, false}, {"Type", 22, false},
+		{"Status", 24, false}, {"Amount", 26, true}, {"Fee", 20, true}, {"Units", 24, true}, {"NAB", 22, true},
+	}
+	gainColumns = []pdfColumn{
+		{"Date", 22, false}, {"Transaction ID", 62, false}, {"Investment", 55, false}, {"Units", 30, true},
+		{"Proceeds", 36, true}, {"Cost basis", 36, true}, {"Gain", 36, true},
+	}
+)
+
+// writePDF lays the statement out as landscape A4 pages with one table per
+// section
+func writePDF(w io.Writer, statement *domain.Statement) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	translate := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Account statement", true)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Account statement", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range []string{
+		"Customer: " + statement.CustomerName + " (" + statement.CustomerID + ")",
+		"Email: " + statement.CustomerEmail,
+		"Period: " + statement.From + " to " + statement.To,
+		"Generated at: " + statement.GeneratedAt.Format(time.RFC3339),
+	} {
+		pdf.CellFormat(0, pdfRowHeight, translate(line), "", 1, "L", false, 0, "")
+	}
+
+	writeSection := func(title string, columns []pdfColumn, rows [][]string) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetFillColor(230, 230, 230)
+		for _, column := range columns {
+			pdf.CellFormat(column.width, pdfRowHeight, column.title, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 8)
+		if len(rows) == 0 {
+			pdf.CellFormat(0, pdfRowHeight, "None", "1", 1, "L", false, 0, "")
+			return
+		}
+		for _, row := range rows {
+			for i, column := range columns {
+				align := "L"
+				if column.numeric {
+					align = "R"
+				}
+				pdf.CellFormat(column.width, pdfRowHeight, translate(row[i]), "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	writeSection("Opening holdings", holdingColumns, holdingRows(statement.OpeningHoldings))
+
+	transactions := [][]string{}
+	for _, transaction := range statement.Transactions {
+		transactions = append(transactions, []string{
+			transaction.TransactionDate.Format(utils.DateLayout),
+			transaction.ID,
+			transaction.InvestmentName,
+			transaction.Type,
+			transaction.Status,
+			formatAmount(transaction.Amount),
+			formatAmount(transaction.Fee),
+			formatUnits(transaction.Units),
+			formatUnits(transaction.NAB),
+		})
+	}
+	writeSection("Transactions", transactionColumns, transactions)
+
+	writeSection("Closing holdings", holdingColumns, holdingRows(statement.ClosingHoldings))
+
+	gains := [][]string{}
+	for _, gain := range statement.RealizedGains {
+		gains = append(gains, []string{
+			gain.Date.Format(utils.DateLayout),
+			gain.TransactionID,
+			gain.InvestmentName,
+			formatUnits(gain.Units),
+			formatAmount(gain.Proceeds),
+			formatAmount(gain.CostBasis),
+			formatAmount(gain.Gain),
+		})
+	}
+	writeSection("Realized gains", gainColumns, gains)
+
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "B", 10)
+	for _, line := range [][2]string{
+		{"Opening value", formatAmount(statement.OpeningValue)},
+		{"Closing value", formatAmount(statement.ClosingValue)},
+		{"Realized gain", formatAmount(statement.TotalRealizedGain)},
+	} {
+		pdf.CellFormat(50, pdfRowHeight, line[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(40, pdfRowHeight, line[1], "", 1, "R", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
+
+func holdingRows(holdings []*domain.StatementHolding) [][]string {
+	rows := [][]string{}
+	for _, holding := range holdings {
+		rows = append(rows, []string{
+			holding.InvestmentID,
+			holding.InvestmentName,
+			formatUnits(holding.Units),
+			formatUnits(holding.NAB),
+			holding.NABDate,
+			formatAmount(holding.Value),
+		})
+	}
+	return rows
+}
@@ -0,0 +1,42 @@
+// Package statement renders customer account statements as files
+package statement
+
+import (
+	"fmt"
+	"io"
+	"nobi-assesment/internal/domain"
+	"strconv"
+)
+
+// Write renders the statement in the given format
+func Write(w io.Writer, statement *domain.Statement, format domain.StatementFormat) error {
+	switch format {
+	case domain.StatementCSV:
+		return writeCSV(w, statement)
+	case domain.StatementPDF:
+		return writePDF(w, statement)
+	default:
+		return domain.ErrInvalidStatementFormat
+	}
+}
+
+// FileName names a statement file after its customer and period
+func FileName(statement *domain.Statement, format domain.StatementFormat) string {
+	return fmt.Sprintf("statement_%s_%s_%s.%s", statement.CustomerID, statement.From, statement.To, format)
+}
+
+// ContentType is the media type of a statement file
+func ContentType(format domain.StatementFormat) string {
+	if format == domain.StatementPDF {
+		return "application/pdf"
+	}
+	return "text/csv"
+}
+
+func formatAmount(value float64) string {
+	return strconv.FormatFloat(value, 'f', 2, 64)
+}
+
+func formatUnits(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
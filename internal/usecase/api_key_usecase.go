@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"strings"
+	"time"
+)
+
+const (
+	// apiKeyScheme starts every key so leaked keys are easy to recognize
+	apiKeyScheme = "nobi_"
+	// apiKeyPrefixLength is the length of the public part of a key
+	apiKeyPrefixLength = 12
+	// apiKeyLastUsedResolution bounds how often the last use of a key is
+	// written, so busy keys do not cost a write per request
+	apiKeyLastUsedResolution = time.Minute
+)
+
+type APIKeyUsecase interface {
+	Issue(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.IssuedAPIKey, error)
+	GetAll(ctx context.Context) ([]*domain.APIKey, error)
+	GetByID(ctx context.Context, id string) (*domain.APIKey, error)
+	// Rotate issues a key with the same scopes as the key id, which keeps
+	// working for the requested overlap so partners can switch over
+	Rotate(ctx context.Context, id string, req *domain.RotateAPIKeyRequest) (*domain.IssuedAPIKey, error)
+	Revoke(ctx context.Context, id string) (*domain.APIKey, error)
+	// Authenticate returns the active key a request presented and records
+	// its use
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+	// SigningSecret returns the secret requests made with a key are signed
+	// with, or an empty string when signing is not configured
+	SigningSecret(key string) string
+}
+
+// PermissionChecker reports whether a principal holds a permission, through
+// its roles or granted directly
+type PermissionChecker interface {
+	Holds(principal *domain.Principal, permission string) bool
+}
+
+type apiKeyUsecase struct {
+	apiKeyRepo  repository.APIKeyRepository
+	transactor  repository.Transactor
+	permissions PermissionChecker
+	signingKey  []byte
+}
+
+// NewAPIKeyUsecase creates an API key usecase. Keys may only be granted
+// permissions their issuer holds, checked with permissions. Signing secrets
+// are derived from each key with signingKey, so they never need to be
+// stored; without one, keys cannot be required to sign requests.
+func NewAPIKeyUsecase(apiKeyRepo repository.APIKeyRepository, transactor repository.Transactor, permissions PermissionChecker, signingKey string) APIKeyUsecase {
+	return &apiKeyUsecase{
+		apiKeyRepo:  apiKeyRepo,
+		transactor:  transactor,
+		permissions: permissions,
+		signingKey:  []byte(signingKey),
+	}
+}
+
+func (u *apiKeyUsecase) Issue(ctx context.Context, req *domain.CreateAPIKeyRequest) (*domain.IssuedAPIKey, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.checkGrantable(ctx, req.Permissions); err != nil {
+		return nil, err
+	}
+	if req.RequireSignature && len(u.signingKey) == 0 {
+		return nil, domain.ErrSigningNotConfigured
+	}
+	if req.CustomerIDs == nil {
+		req.CustomerIDs = []string{}
+	}
+
+	return u.issue(ctx, &domain.APIKey{
+		Name:             strings.TrimSpace(req.Name),
+		Permissions:      req.Permissions,
+		CustomerIDs:      req.CustomerIDs,
+		ExpiresAt:        req.ExpiresAt,
+		RequireSignature: req.RequireSignature,
+	})
+}
+
+// checkGrantable rejects permissions the caller does not hold itself, so a
+// key never does more than the staff member who issued or rotated it.
+// Requests without a caller are only possible when authentication is
+// disabled.
+func (u *apiKeyUsecase) checkGrantable(ctx context.Context, permissions []string) error {
+	principal, ok := domain.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	for _, permission := range permissions {
+		if !u.permissions.Holds(principal, permission) {
+			return domain.ErrForbidden.WithMessage("cannot grant permission " + permission + " without holding it")
+		}
+	}
+	return nil
+}
+
+func (u *apiKeyUsecase) issue(ctx context.Context, key *domain.APIKey) (*domain.IssuedAPIKey, error) {
+	secret, prefix, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key.ID = utils.GenerateUUID()
+	key.Prefix = prefix
+	key.KeyHash = hashAPIKey(secret)
+	if err := u.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	created, err := u.apiKeyRepo.GetByID(ctx, key.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.IssuedAPIKey{APIKey: created, Key: secret, SigningSecret: u.SigningSecret(secret)}, nil
+}
+
+func (u *apiKeyUsecase) GetAll(ctx context.Context) ([]*domain.APIKey, error) {
+	return u.apiKeyRepo.GetAll(ctx)
+}
+
+func (u *apiKeyUsecase) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := u.apiKeyRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+func (u *apiKeyUsecase) Rotate(ctx context.Context, id string, req *domain.RotateAPIKeyRequest) (*domain.IssuedAPIKey, error) {
+	var overlap time.Duration
+	if req.Overlap != "" {
+		var err error
+		if overlap, err = time.ParseDuration(req.Overlap); err != nil || overlap < 0 {
+			return nil, domain.ErrInvalidAPIKeyOverlap
+		}
+	}
+
+	var issued *domain.IssuedAPIKey
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		old, err := u.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if old.RevokedAt != nil {
+			return domain.ErrAPIKeyRevoked
+		}
+		if err := u.checkGrantable(ctx, old.Permissions); err != nil {
+			return err
+		}
+
+		issued, err = u.issue(ctx, &domain.APIKey{
+			Name:             old.Name,
+			Permissions:      old.Permissions,
+			CustomerIDs:      old.CustomerIDs,
+			RotatedFromID:    old.ID,
+			RequireSignature: old.RequireSignature,
+		})
+		if err != nil {
+			return err
+		}
+
+		// The old key never outlives its own expiry
+		until := time.Now().Add(overlap)
+		if old.ExpiresAt != nil && old.ExpiresAt.Before(until) {
+			until = *old.ExpiresAt
+		}
+		return u.apiKeyRepo.SetExpiry(ctx, old.ID, &until, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issued, nil
+}
+
+func (u *apiKeyUsecase) Revoke(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	now := time.Now()
+	if err := u.apiKeyRepo.SetExpiry(ctx, id, key.ExpiresAt, &now); err != nil {
+		return nil, err
+	}
+
+	return u.apiKeyRepo.GetByID(ctx, id)
+}
+
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, secret string) (*domain.APIKey, error) {
+	prefix, ok := parseAPIKey(secret)
+	if !ok {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key, err := u.apiKeyRepo.GetByPrefix(ctx, prefix)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(hashAPIKey(secret)), []byte(key.KeyHash)) != 1 || !key.IsActive(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedResolution {
+		// Failing to record the use must not fail the request
+		if err := u.apiKeyRepo.TouchLastUsed(ctx, key.ID, now); err != nil {
+			slog.WarnContext(ctx, "failed to record use of api key", "api_key_id", key.ID, "error", err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+
+	return key, nil
+}
+
+func (u *apiKeyUsecase) SigningSecret(key string) string {
+	if len(u.signingKey) == 0 {
+		return ""
+	}
+	mac := hmac.New(sha256.New, u.signingKey)
+	mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateAPIKey returns a new random key and its public prefix. Keys are
+// formatted nobi_<prefix>_<secret>, both parts hexadecimal.
+func generateAPIKey() (key, prefix string, err error) {
+	random := make([]byte, apiKeyPrefixLength/2+32)
+	if _, err := rand.Read(random); err != nil {
+		return "", "", err
+	}
+
+	prefix = hex.EncodeToString(random[:apiKeyPrefixLength/2])
+	return apiKeyScheme + prefix + "_" + hex.EncodeToString(random[apiKeyPrefixLength/2:]), prefix, nil
+}
+
+// parseAPIKey returns the prefix of a well-formed key
+func parseAPIKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, apiKeyScheme)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != apiKeyPrefixLength || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// hashAPIKey hashes a key for storage. Keys are random, so a fast hash does
+// not make them guessable the way it would passwords.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"slices"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := generateAPIKey()
+	if err != nil {
+		t.Fatalf("generateAPIKey() error = %v", err)
+	}
+
+	parsed, ok := parseAPIKey(key)
+	if !ok || parsed != prefix {
+		t.Errorf("parseAPIKey(%q) = %q, %v, want %q", key, parsed, ok, prefix)
+	}
+
+	other, _, _ := generateAPIKey()
+	if other == key || hashAPIKey(other) == hashAPIKey(key) {
+		t.Errorf("generateAPIKey() returned the same key twice")
+	}
+}
+
+func TestParseAPIKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{"Valid", "nobi_0123456789ab_secret", true},
+		{"Missing scheme", "0123456789ab_secret", false},
+		{"Short prefix", "nobi_0123_secret", false},
+		{"Missing secret", "nobi_0123456789ab_", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := parseAPIKey(tt.key); ok != tt.want {
+				t.Errorf("parseAPIKey(%q) ok = %v, want %v", tt.key, ok, tt.want)
+			}
+		})
+	}
+}
+
+// grantedPermissions holds exactly the permissions listed
+type grantedPermissions []string
+
+func (g grantedPermissions) Holds(principal *domain.Principal, permission string) bool {
+	return slices.Contains(g, permission)
+}
+
+func TestCheckGrantable(t *testing.T) {
+	u := &apiKeyUsecase{permissions: grantedPermissions{"transactions:write", "customers:read"}}
+	operator := domain.WithPrincipal(context.Background(), &domain.Principal{Subject: "operator", Roles: []string{domain.RoleOperator}})
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		permissions []string
+		wantErr     error
+	}{
+		{"Held permissions", operator, []string{"transactions:write", "customers:read"}, nil},
+		{"Everything", operator, []string{"*"}, domain.ErrForbidden},
+		{"Permission not held", operator, []string{"transactions:write", "transactions:reverse"}, domain.ErrForbidden},
+		{"Authentication disabled", context.Background(), []string{"*"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := u.checkGrantable(tt.ctx, tt.permissions); !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkGrantable(%v) error = %v, want %v", tt.permissions, err, tt.wantErr)
+			}
+		})
+	}
+}
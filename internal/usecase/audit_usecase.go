@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+// defaultAuditLimit is how many logs an audit query returns unless told otherwise
+const defaultAuditLimit = 100
+
+type AuditUsecase interface {
+	GetAll(ctx context.Context, filter *domain.AuditLogFilter) ([]*domain.AuditLog, error)
+	// Verify recomputes the tenant's hash chain and reports the first log
+	// that was altered, removed or inserted
+	Verify(ctx context.Context) (*domain.AuditVerification, error)
+}
+
+type auditUsecase struct {
+	auditRepo  repository.AuditLogRepository
+	transactor repository.Transactor
+}
+
+func NewAuditUsecase(auditRepo repository.AuditLogRepository, transactor repository.Transactor) AuditUsecase {
+	return &auditUsecase{
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+func (u *auditUsecase) GetAll(ctx context.Context, filter *domain.AuditLogFilter) ([]*domain.AuditLog, error) {
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return u.auditRepo.GetAll(ctx, filter)
+}
+
+func (u *auditUsecase) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	verification := &domain.AuditVerification{Valid: true}
+
+	// Read the head and the chain from one snapshot so logs written
+	// meanwhile are not mistaken for tampering
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		headSequence, headHash, err := u.auditRepo.GetChainHead(ctx)
+		if err != nil {
+			return err
+		}
+
+		var sequence int64
+		var prevHash string
+		err = u.auditRepo.StreamChain(ctx, func(log *domain.AuditLog) error {
+			verification.Entries++
+			if problem := checkAuditLink(log, sequence+1, prevHash); problem != "" && verification.Valid {
+				verification.Valid = false
+				verification.BrokenAt = log.Sequence
+				verification.Problem = problem
+			}
+			sequence, prevHash = log.Sequence, log.Hash
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if verification.Valid && (sequence != headSequence || prevHash != headHash) {
+			verification.Valid = false
+			verification.BrokenAt = sequence + 1
+			verification.Problem = fmt.Sprintf("chain ends at sequence %d, its head is at %d", sequence, headSequence)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	verification.VerifiedAt = time.Now()
+	return verification, nil
+}
+
+// checkAuditLink describes why the log cannot follow the log with the given
+// hash at the given sequence, or returns an empty string when it can
+func checkAuditLink(log *domain.AuditLog, sequence int64, prevHash string) string {
+	switch {
+	case log.Sequence != sequence:
+		return fmt.Sprintf("expected sequence %d, found %d", sequence, log.Sequence)
+	case log.PrevHash != prevHash:
+		return "previous hash does not match the previous log"
+	case log.Hash != log.ComputeHash():
+		return "hash does not match the log's contents"
+	}
+	return ""
+}
+
+// recordAudit appends a change to the audit log, made by the principal and
+// request of ctx. Before and after are the entity around the change, nil
+// when it did not exist.
+func recordAudit(ctx context.Context, auditRepo repository.AuditLogRepository, entityType, entityID, action, reason string, before, after any) error {
+	log := &domain.AuditLog{
+		ID:         utils.GenerateUUID(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Reason:     reason,
+		Actor:      domain.AuditActorSystem,
+		CreatedAt:  time.Now(),
+	}
+	if principal, ok := domain.PrincipalFromContext(ctx); ok {
+		log.Actor = principal.Subject
+	}
+	request := domain.RequestInfoFromContext(ctx)
+	log.RequestID = request.ID
+	log.IPAddress = request.IP
+
+	var err error
+	if log.Before, err = auditSnapshot(before); err != nil {
+		return err
+	}
+	if log.After, err = auditSnapshot(after); err != nil {
+		return err
+	}
+
+	if err := auditRepo.Create(ctx, log); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "audit log recorded",
+		"action", log.Action,
+		"entity_type", log.EntityType,
+		"entity_id", log.EntityID,
+		"sequence", log.Sequence)
+	return nil
+}
+
+// logFailure logs why an operation failed, at warn level for domain errors
+// the caller can correct and at error level otherwise
+func logFailure(ctx context.Context, msg string, err error) {
+	level := slog.LevelError
+	if _, ok := domain.AsError(err); ok {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, msg, "error", err)
+}
+
+// auditSnapshot encodes an entity as JSON, returning nil for nil entities
+func auditSnapshot(entity any) (json.RawMessage, error) {
+	data, err := json.Marshal(entity)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
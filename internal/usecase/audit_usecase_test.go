@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"encoding/json"
+	"nobi-assesment/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestCheckAuditLink(t *testing.T) {
+	newLog := func(sequence int64, prevHash string) *domain.AuditLog {
+		log := &domain.AuditLog{
+			ID:         "log-1",
+			Sequence:   sequence,
+			EntityType: domain.AuditEntityCustomer,
+			EntityID:   "c1",
+			Action:     domain.AuditActionCustomerUpdated,
+			Actor:      "user-1",
+			Before:     json.RawMessage(`{"name":"Old"}`),
+			After:      json.RawMessage(`{"name":"New"}`),
+			PrevHash:   prevHash,
+			CreatedAt:  time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC),
+		}
+		log.Hash = log.ComputeHash()
+		return log
+	}
+
+	tampered := newLog(2, "abc")
+	tampered.After = json.RawMessage(`{"name":"Forged"}`)
+
+	tests := []struct {
+		name     string
+		log      *domain.AuditLog
+		sequence int64
+		prevHash string
+		wantOK   bool
+	}{
+		{"intact", newLog(2, "abc"), 2, "abc", true},
+		{"first log", newLog(1, ""), 1, "", true},
+		{"gap in sequence", newLog(3, "abc"), 2, "abc", false},
+		{"previous log changed", newLog(2, "abc"), 2, "def", false},
+		{"contents changed", tampered, 2, "abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := checkAuditLink(tt.log, tt.sequence, tt.prevHash)
+			if (problem == "") != tt.wantOK {
+				t.Errorf("checkAuditLink() = %q, want ok %v", problem, tt.wantOK)
+			}
+		})
+	}
+}
@@ -2,35 +2,253 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"nobi-assesment/internal/domain"
 	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/utils"
+	"strings"
 )
 
 type CustomerUsecase interface {
 	Create(ctx context.Context, customer *domain.Customer) error
 	GetByID(ctx context.Context, id string) (*domain.Customer, error)
 	GetAll(ctx context.Context) ([]*domain.Customer, error)
+	Update(ctx context.Context, id string, req *domain.UpdateCustomerRequest) (*domain.Customer, error)
+	Deactivate(ctx context.Context, id string, reason string) (*domain.Customer, error)
+	Reactivate(ctx context.Context, id string, reason string) (*domain.Customer, error)
+	UpdateKYCStatus(ctx context.Context, id string, req *domain.KYCStatusRequest) (*domain.Customer, error)
 }
 
 type customerUsecase struct {
 	customerRepo repository.CustomerRepository
+	auditRepo    repository.AuditLogRepository
+	transactor   repository.Transactor
 }
 
-func NewCustomerUsecase(customerRepo repository.CustomerRepository) CustomerUsecase {
+func NewCustomerUsecase(
+	customerRepo repository.CustomerRepository,
+	auditRepo repository.AuditLogRepository,
+	transactor repository.Transactor,
+) CustomerUsecase {
 	return &customerUsecase{
 		customerRepo: customerRepo,
+		auditRepo:    auditRepo,
+		transactor:   transactor,
 	}
 }
 
+// Create stores a new customer. Name uniqueness is enforced by the
+// repository, which reports a duplicate as domain.ErrCustomerNameTaken.
 func (u *customerUsecase) Create(ctx context.Context, customer *domain.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	if customer.Name == "" {
+		return domain.ErrCustomerNameRequired
+	}
+	if err := validateProfile(customer); err != nil {
+		return err
+	}
+
 	customer.IsActive = true
-	return u.customerRepo.Create(ctx, customer)
+	customer.KYCStatus = domain.KYCUnverified
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.customerRepo.Create(ctx, customer); err != nil {
+			return err
+		}
+		return u.audit(ctx, domain.AuditActionCustomerCreated, "", nil, customer)
+	})
 }
 
 func (u *customerUsecase) GetByID(ctx context.Context, id string) (*domain.Customer, error) {
-	return u.customerRepo.GetByID(ctx, id)
+	customer, err := u.customerRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return customer, err
 }
 
 func (u *customerUsecase) GetAll(ctx context.Context) ([]*domain.Customer, error) {
 	return u.customerRepo.GetAll(ctx)
 }
+
+func (u *customerUsecase) Update(ctx context.Context, id string, req *domain.UpdateCustomerRequest) (*domain.Customer, error) {
+	ctx = logger.WithAttrs(ctx, slog.String("customer_id", id))
+	customer, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *customer
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, domain.ErrCustomerNameRequired
+		}
+		customer.Name = name
+	}
+	applyString(&customer.Email, req.Email)
+	applyString(&customer.Phone, req.Phone)
+	applyString(&customer.IDNumber, req.IDNumber)
+	applyString(&customer.DateOfBirth, req.DateOfBirth)
+	applyString(&customer.Address, req.Address)
+
+	if err := validateProfile(customer); err != nil {
+		return nil, err
+	}
+
+	// Identity changes invalidate any verification done on the old data.
+	// The status is changed by its own statement, so a profile update never
+	// writes back a status changed since it was read.
+	identityChanged := customer.IDNumber != before.IDNumber ||
+		customer.DateOfBirth != before.DateOfBirth ||
+		customer.Address != before.Address
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.customerRepo.Update(ctx, customer); err != nil {
+			return err
+		}
+		if identityChanged {
+			if err := u.customerRepo.InvalidateKYC(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		updated, err := u.customerRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		customer = updated
+		return u.audit(ctx, domain.AuditActionCustomerUpdated, "", &before, customer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+func (u *customerUsecase) Deactivate(ctx context.Context, id string, reason string) (*domain.Customer, error) {
+	return u.setActive(ctx, id, false, reason)
+}
+
+func (u *customerUsecase) Reactivate(ctx context.Context, id string, reason string) (*domain.Customer, error) {
+	return u.setActive(ctx, id, true, reason)
+}
+
+func (u *customerUsecase) setActive(ctx context.Context, id string, isActive bool, reason string) (*domain.Customer, error) {
+	ctx = logger.WithAttrs(ctx, slog.String("customer_id", id))
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	customer, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.IsActive == isActive {
+		if isActive {
+			return nil, domain.ErrCustomerAlreadyActive
+		}
+		return nil, domain.ErrCustomerAlreadyInactive
+	}
+	before := *customer
+
+	customer.IsActive = isActive
+
+	action := domain.AuditActionCustomerDeactivated
+	if isActive {
+		action = domain.AuditActionCustomerReactivated
+	}
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.customerRepo.UpdateActiveStatus(ctx, id, isActive); err != nil {
+			return err
+		}
+		return u.audit(ctx, action, reason, &before, customer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+func (u *customerUsecase) UpdateKYCStatus(ctx context.Context, id string, req *domain.KYCStatusRequest) (*domain.Customer, error) {
+	ctx = logger.WithAttrs(ctx, slog.String("customer_id", id))
+	if !req.Status.Valid() {
+		return nil, domain.ErrInvalidKYCStatus
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if req.Status == domain.KYCRejected && reason == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	customer, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !customer.KYCStatus.CanTransitionTo(req.Status) {
+		return nil, domain.ErrInvalidKYCTransition
+	}
+
+	if req.Status == domain.KYCPending || req.Status == domain.KYCVerified {
+		if customer.IDNumber == "" || customer.DateOfBirth == "" || customer.Address == "" {
+			return nil, domain.ErrKYCProfileIncomplete
+		}
+	}
+	before := *customer
+
+	customer.KYCStatus = req.Status
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Conditional on the status the transition was checked against, in
+		// case it changed since
+		updated, err := u.customerRepo.UpdateKYCStatus(ctx, id, before.KYCStatus, req.Status)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return domain.ErrInvalidKYCTransition
+		}
+		return u.audit(ctx, domain.AuditActionCustomerKYCUpdated, reason, &before, customer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// audit records a customer change in the audit trail
+func (u *customerUsecase) audit(ctx context.Context, action, reason string, before, after *domain.Customer) error {
+	return recordAudit(ctx, u.auditRepo, domain.AuditEntityCustomer, after.ID, action, reason, before, after)
+}
+
+// validateProfile checks the format of the optional contact and identity fields
+func validateProfile(customer *domain.Customer) error {
+	if customer.Email != "" && !utils.ValidateEmail(customer.Email) {
+		return domain.ErrInvalidEmail
+	}
+	if customer.Phone != "" && !utils.ValidatePhone(customer.Phone) {
+		return domain.ErrInvalidPhone
+	}
+	if customer.IDNumber != "" && !utils.ValidateIDNumber(customer.IDNumber) {
+		return domain.ErrInvalidIDNumber
+	}
+	if customer.DateOfBirth != "" && !utils.ValidateDateOfBirth(customer.DateOfBirth) {
+		return domain.ErrInvalidDateOfBirth
+	}
+	return nil
+}
+
+// applyString overwrites dst with the trimmed value when one was supplied
+func applyString(dst *string, value *string) {
+	if value != nil {
+		*dst = strings.TrimSpace(*value)
+	}
+}
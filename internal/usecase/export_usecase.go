@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/export"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+type ExportUsecase interface {
+	// Validate checks a dataset and format before anything is written
+	Validate(dataset domain.ExportDataset, format domain.ExportFormat) error
+	// Export streams the rows of a dataset updated at or after since into w,
+	// oldest update first. The returned watermark is the since of the next
+	// incremental export.
+	Export(ctx context.Context, dataset domain.ExportDataset, format domain.ExportFormat, since time.Time, w io.Writer) (*domain.ExportResult, error)
+}
+
+type exportUsecase struct {
+	exportRepo repository.ExportRepository
+}
+
+func NewExportUsecase(exportRepo repository.ExportRepository) ExportUsecase {
+	return &exportUsecase{
+		exportRepo: exportRepo,
+	}
+}
+
+func (u *exportUsecase) Validate(dataset domain.ExportDataset, format domain.ExportFormat) error {
+	if !dataset.IsValid() {
+		return domain.ErrInvalidExportDataset
+	}
+	if !format.IsValid() {
+		return domain.ErrInvalidExportFormat
+	}
+	return nil
+}
+
+func (u *exportUsecase) Export(ctx context.Context, dataset domain.ExportDataset, format domain.ExportFormat, since time.Time, w io.Writer) (*domain.ExportResult, error) {
+	if err := u.Validate(dataset, format); err != nil {
+		return nil, err
+	}
+
+	result := &domain.ExportResult{Dataset: dataset, Watermark: since}
+	var err error
+	switch dataset {
+	case domain.ExportTransactions:
+		err = exportRows(format, w, result, func(fn func(*domain.TransactionExport) error) error {
+			return u.exportRepo.StreamTransactions(ctx, since, fn)
+		}, func(row *domain.TransactionExport) time.Time { return row.UpdatedAt })
+	case domain.ExportHoldings:
+		err = exportRows(format, w, result, func(fn func(*domain.HoldingExport) error) error {
+			return u.exportRepo.StreamHoldings(ctx, since, fn)
+		}, func(row *domain.HoldingExport) time.Time { return row.UpdatedAt })
+	case domain.ExportNABHistory:
+		err = exportRows(format, w, result, func(fn func(*domain.NABExport) error) error {
+			return u.exportRepo.StreamNABHistory(ctx, since, fn)
+		}, func(row *domain.NABExport) time.Time { return row.UpdatedAt })
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// exportRows writes each streamed row as it arrives, counting the rows and
+// advancing the watermark to the latest update
+func exportRows[T any](
+	format domain.ExportFormat,
+	w io.Writer,
+	result *domain.ExportResult,
+	stream func(fn func(*T) error) error,
+	updatedAt func(*T) time.Time,
+) error {
+	writer, err := export.NewWriter[T](format, w)
+	if err != nil {
+		return err
+	}
+
+	err = stream(func(row *T) error {
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+		result.Rows++
+		if t := updatedAt(row); t.After(result.Watermark) {
+			result.Watermark = t
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// ParseSince parses the since of an incremental export, either an RFC 3339
+// time or a YYYY-MM-DD date. An empty value exports everything.
+func ParseSince(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+	since, err := time.ParseInLocation(utils.DateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidExportSince
+	}
+	return since, nil
+}
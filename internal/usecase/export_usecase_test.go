@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"errors"
+	"nobi-assesment/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  time.Time
+		err   error
+	}{
+		{"Empty", "", time.Time{}, nil},
+		{"Date", "2025-01-31", time.Date(2025, time.January, 31, 0, 0, 0, 0, time.Local), nil},
+		{"Time", "2025-01-31T10:30:00Z", time.Date(2025, time.January, 31, 10, 30, 0, 0, time.UTC), nil},
+		{"Invalid", "31-01-2025", time.Time{}, domain.ErrInvalidExportSince},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSince(tt.value)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("ParseSince() error = %v, want %v", err, tt.err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"strconv"
+	"strings"
+)
+
+// maxImportLine bounds the length of a JSON lines record
+const maxImportLine = 1 << 20
+
+// errDryRun rolls back the batch transaction of a dry run
+var errDryRun = errors.New("dry run")
+
+type ImportUsecase interface {
+	// Import creates the customers or investments read from r, one batch
+	// transaction at a time. Rows failing validation are reported and
+	// skipped. A dry run validates every row against the database and rolls
+	// each batch back.
+	Import(ctx context.Context, entity domain.ImportEntity, format domain.ImportFormat, r io.Reader, dryRun bool) (*domain.ImportReport, error)
+}
+
+type importUsecase struct {
+	customerUsecase   CustomerUsecase
+	investmentUsecase InvestmentUsecase
+	transactor        repository.Transactor
+	batchSize         int
+}
+
+func NewImportUsecase(
+	customerUsecase CustomerUsecase,
+	investmentUsecase InvestmentUsecase,
+	transactor repository.Transactor,
+	batchSize int,
+) ImportUsecase {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &importUsecase{
+		customerUsecase:   customerUsecase,
+		investmentUsecase: investmentUsecase,
+		transactor:        transactor,
+		batchSize:         batchSize,
+	}
+}
+
+func (u *importUsecase) Import(ctx context.Context, entity domain.ImportEntity, format domain.ImportFormat, r io.Reader, dryRun bool) (*domain.ImportReport, error) {
+	var create func(ctx context.Context, record *importRecord) (string, error)
+	var columns map[string]bool
+	switch entity {
+	case domain.ImportCustomers:
+		create, columns = u.createCustomer, columnSet(customerImportColumns)
+	case domain.ImportInvestments:
+		create, columns = u.createInvestment, columnSet(investmentImportColumns)
+	default:
+		return nil, domain.ErrInvalidImportEntity
+	}
+
+	next, err := newRecordReader(format, r, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{Entity: entity, DryRun: dryRun, Rows: []*domain.ImportRowResult{}}
+	batch := make([]*importRecord, 0, u.batchSize)
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		batch = append(batch, record)
+		if len(batch) < u.batchSize {
+			continue
+		}
+		if err := u.importBatch(ctx, batch, create, dryRun, report); err != nil {
+			return nil, err
+		}
+		batch = batch[:0]
+	}
+	if len(batch) > 0 {
+		if err := u.importBatch(ctx, batch, create, dryRun, report); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// importBatch creates the records of one batch in a single transaction. Rows
+// rejected with a domain error are reported as failed, any other error
+// aborts the import.
+func (u *importUsecase) importBatch(
+	ctx context.Context,
+	batch []*importRecord,
+	create func(ctx context.Context, record *importRecord) (string, error),
+	dryRun bool,
+	report *domain.ImportReport,
+) error {
+	var results []*domain.ImportRowResult
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		results = make([]*domain.ImportRowResult, 0, len(batch))
+		for _, record := range batch {
+			result := &domain.ImportRowResult{Row: record.row, Status: domain.ImportRowCreated}
+			if dryRun {
+				result.Status = domain.ImportRowValid
+			}
+
+			id, err := create(ctx, record)
+			if err != nil {
+				e, ok := domain.AsError(err)
+				if !ok {
+					return err
+				}
+				result.Status = domain.ImportRowFailed
+				result.Code = e.Code
+				result.Error = e.Message
+			} else if !dryRun {
+				result.ID = id
+			}
+
+			results = append(results, result)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return err
+	}
+
+	for _, result := range results {
+		report.Total++
+		if result.Status == domain.ImportRowFailed {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+
+	return nil
+}
+
+func (u *importUsecase) createCustomer(ctx context.Context, record *importRecord) (string, error) {
+	customer, err := decodeRecord(record, customerImportColumns)
+	if err != nil {
+		return "", err
+	}
+
+	customer.ID = utils.GenerateUUID()
+	if err := u.customerUsecase.Create(ctx, customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+func (u *importUsecase) createInvestment(ctx context.Context, record *importRecord) (string, error) {
+	investment, err := decodeRecord(record, investmentImportColumns)
+	if err != nil {
+		return "", err
+	}
+
+	investment.ID = utils.GenerateUUID()
+	if err := u.investmentUsecase.Create(ctx, investment); err != nil {
+		return "", err
+	}
+	return investment.ID, nil
+}
+
+// importRecord is one record of an import file, holding either the values of
+// a CSV row by column or a JSON object
+type importRecord struct {
+	row    int
+	values map[string]string
+	raw    []byte
+	err    error // Why the record could not be read, reported on its row
+}
+
+// customerImportColumns sets the customer field each CSV column holds
+var customerImportColumns = map[string]func(*domain.Customer, string) error{
+	"name":          func(c *domain.Customer, v string) error { c.Name = v; return nil },
+	"email":         func(c *domain.Customer, v string) error { c.Email = v; return nil },
+	"phone":         func(c *domain.Customer, v string) error { c.Phone = v; return nil },
+	"id_number":     func(c *domain.Customer, v string) error { c.IDNumber = v; return nil },
+	"date_of_birth": func(c *domain.Customer, v string) error { c.DateOfBirth = v; return nil },
+	"address":       func(c *domain.Customer, v string) error { c.Address = v; return nil },
+}
+
+// investmentImportColumns sets the investment field each CSV column holds.
+// Rules are flattened into their own columns.
+var investmentImportColumns = map[string]func(*domain.Investment, string) error{
+	"name":           func(i *domain.Investment, v string) error { i.Name = v; return nil },
+	"description":    func(i *domain.Investment, v string) error { i.Description = v; return nil },
+	"risk_level":     func(i *domain.Investment, v string) error { i.RiskLevel = domain.RiskLevel(v); return nil },
+	"category":       func(i *domain.Investment, v string) error { i.Category = domain.FundCategory(v); return nil },
+	"currency":       func(i *domain.Investment, v string) error { i.Currency = v; return nil },
+	"manager":        func(i *domain.Investment, v string) error { i.Manager = v; return nil },
+	"inception_date": func(i *domain.Investment, v string) error { i.InceptionDate = v; return nil },
+	"nab":            func(i *domain.Investment, v string) error { return parseAmount(&i.NAB, v) },
+	"total_units":    func(i *domain.Investment, v string) error { return parseAmount(&i.TotalUnits, v) },
+	"total_balance":  func(i *domain.Investment, v string) error { return parseAmount(&i.TotalBalance, v) },
+	"min_initial_subscription": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.MinInitialSubscription, v)
+	},
+	"min_subsequent_subscription": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.MinSubsequentSubscription, v)
+	},
+	"min_redemption": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.MinRedemption, v)
+	},
+	"min_remaining_balance": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.MinRemainingBalance, v)
+	},
+	"max_holding_per_customer": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.MaxHoldingPerCustomer, v)
+	},
+	"daily_subscription_limit": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.DailySubscriptionLimit, v)
+	},
+	"daily_redemption_limit": func(i *domain.Investment, v string) error {
+		return parseAmount(&i.Rules.DailyRedemptionLimit, v)
+	},
+}
+
+func parseAmount(field *float64, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", value)
+	}
+	*field = amount
+	return nil
+}
+
+func columnSet[T any](columns map[string]func(*T, string) error) map[string]bool {
+	set := make(map[string]bool, len(columns))
+	for column := range columns {
+		set[column] = true
+	}
+	return set
+}
+
+// decodeRecord builds the entity a record describes, reporting unreadable
+// records and values as domain.ErrInvalidImportRow
+func decodeRecord[T any](record *importRecord, columns map[string]func(*T, string) error) (*T, error) {
+	if record.err != nil {
+		return nil, domain.ErrInvalidImportRow.WithMessage(record.err.Error())
+	}
+
+	entity := new(T)
+	if record.raw != nil {
+		if err := json.Unmarshal(record.raw, entity); err != nil {
+			return nil, domain.ErrInvalidImportRow.WithMessage(err.Error())
+		}
+		return entity, nil
+	}
+
+	for column, value := range record.values {
+		if err := columns[column](entity, value); err != nil {
+			return nil, domain.ErrInvalidImportRow.WithMessage(column + ": " + err.Error())
+		}
+	}
+	return entity, nil
+}
+
+// newRecordReader returns a function reading the next record of an import
+// file, or io.EOF after the last one. CSV files must start with a header
+// naming known columns.
+func newRecordReader(format domain.ImportFormat, r io.Reader, columns map[string]bool) (func() (*importRecord, error), error) {
+	switch format {
+	case domain.ImportCSV:
+		reader := csv.NewReader(r)
+		reader.TrimLeadingSpace = true
+
+		header, err := reader.Read()
+		if err == io.EOF {
+			return nil, domain.ErrInvalidImportFile.WithMessage("import file is empty")
+		}
+		if err != nil {
+			return nil, domain.ErrInvalidImportFile.WithMessage(err.Error())
+		}
+		for i, column := range header {
+			header[i] = strings.ToLower(strings.TrimSpace(column))
+			if !columns[header[i]] {
+				return nil, domain.ErrInvalidImportFile.WithMessage(fmt.Sprintf("unknown column %q", column))
+			}
+		}
+
+		row := 0
+		return func() (*importRecord, error) {
+			fields, err := reader.Read()
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			row++
+			if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+				return nil, domain.ErrInvalidImportFile.WithMessage(err.Error())
+			}
+
+			record := &importRecord{row: row, values: make(map[string]string, len(header))}
+			if err != nil {
+				record.err = fmt.Errorf("row has %d columns, want %d", len(fields), len(header))
+				return record, nil
+			}
+			for i, value := range fields {
+				record.values[header[i]] = strings.TrimSpace(value)
+			}
+			return record, nil
+		}, nil
+
+	case domain.ImportJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxImportLine)
+
+		line := 0
+		return func() (*importRecord, error) {
+			for scanner.Scan() {
+				line++
+				raw := bytes.TrimSpace(scanner.Bytes())
+				if len(raw) == 0 {
+					continue
+				}
+				return &importRecord{row: line, raw: append([]byte(nil), raw...)}, nil
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, domain.ErrInvalidImportFile.WithMessage(err.Error())
+			}
+			return nil, io.EOF
+		}, nil
+
+	default:
+		return nil, domain.ErrInvalidImportFormat
+	}
+}
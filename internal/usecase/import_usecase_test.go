@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"nobi-assesment/internal/domain"
+	"strings"
+	"testing"
+)
+
+func readRecords(t *testing.T, format domain.ImportFormat, input string) []*importRecord {
+	t.Helper()
+
+	next, err := newRecordReader(format, strings.NewReader(input), columnSet(investmentImportColumns))
+	if err != nil {
+		t.Fatalf("newRecordReader() error = %v", err)
+	}
+
+	records := []*importRecord{}
+	for {
+		record, err := next()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("next() error = %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestImportCSV(t *testing.T) {
+	input := "Name, nab, min_redemption\nEquity Fund,1.5,100\nBond Fund,abc,0\nShort Row\n"
+
+	records := readRecords(t, domain.ImportCSV, input)
+	if len(records) != 3 {
+		t.Fatalf("read %d records, want 3", len(records))
+	}
+
+	investment, err := decodeRecord(records[0], investmentImportColumns)
+	if err != nil {
+		t.Fatalf("decodeRecord() error = %v", err)
+	}
+	if investment.Name != "Equity Fund" || investment.NAB != 1.5 || investment.Rules.MinRedemption != 100 {
+		t.Errorf("decodeRecord() = %+v, want Equity Fund with NAB 1.5 and min redemption 100", investment)
+	}
+
+	for _, record := range records[1:] {
+		if _, err := decodeRecord(record, investmentImportColumns); !errors.Is(err, domain.ErrInvalidImportRow) {
+			t.Errorf("decodeRecord() row %d error = %v, want %v", record.row, err, domain.ErrInvalidImportRow)
+		}
+	}
+}
+
+func TestImportJSONLines(t *testing.T) {
+	input := "{\"name\": \"Equity Fund\", \"rules\": {\"min_redemption\": 100}}\n\n{\"name\": 5}\n"
+
+	records := readRecords(t, domain.ImportJSONL, input)
+	if len(records) != 2 {
+		t.Fatalf("read %d records, want 2", len(records))
+	}
+	if records[1].row != 3 {
+		t.Errorf("second record row = %d, want line 3", records[1].row)
+	}
+
+	investment, err := decodeRecord(records[0], investmentImportColumns)
+	if err != nil {
+		t.Fatalf("decodeRecord() error = %v", err)
+	}
+	if investment.Name != "Equity Fund" || investment.Rules.MinRedemption != 100 {
+		t.Errorf("decodeRecord() = %+v, want Equity Fund with min redemption 100", investment)
+	}
+
+	if _, err := decodeRecord(records[1], investmentImportColumns); !errors.Is(err, domain.ErrInvalidImportRow) {
+		t.Errorf("decodeRecord() error = %v, want %v", err, domain.ErrInvalidImportRow)
+	}
+}
+
+func TestImportInvalidFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		format domain.ImportFormat
+		input  string
+		want   error
+	}{
+		{"Empty CSV", domain.ImportCSV, "", domain.ErrInvalidImportFile},
+		{"Unknown column", domain.ImportCSV, "name,colour\n", domain.ErrInvalidImportFile},
+		{"Unknown format", domain.ImportFormat("xml"), "", domain.ErrInvalidImportFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newRecordReader(tt.format, strings.NewReader(tt.input), columnSet(customerImportColumns))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("newRecordReader() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
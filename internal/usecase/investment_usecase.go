@@ -2,34 +2,182 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"nobi-assesment/internal/domain"
 	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/logger"
+	"nobi-assesment/pkg/utils"
+	"regexp"
+	"strings"
+	"time"
 )
 
+// defaultCurrency is used when an investment is created without a currency
+const defaultCurrency = "IDR"
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 type InvestmentUsecase interface {
 	Create(ctx context.Context, investment *domain.Investment) error
 	GetByID(ctx context.Context, id string) (*domain.Investment, error)
 	GetAll(ctx context.Context) ([]*domain.Investment, error)
+	Update(ctx context.Context, id string, req *domain.UpdateInvestmentRequest) (*domain.Investment, error)
 }
 
 type investmentUsecase struct {
 	investmentRepo repository.InvestmentRepository
+	auditRepo      repository.AuditLogRepository
+	ledgerRepo     repository.LedgerRepository
+	transactor     repository.Transactor
 }
 
-func NewInvestmentUsecase(investmentRepo repository.InvestmentRepository) InvestmentUsecase {
+func NewInvestmentUsecase(
+	investmentRepo repository.InvestmentRepository,
+	auditRepo repository.AuditLogRepository,
+	ledgerRepo repository.LedgerRepository,
+	transactor repository.Transactor,
+) InvestmentUsecase {
 	return &investmentUsecase{
 		investmentRepo: investmentRepo,
+		auditRepo:      auditRepo,
+		ledgerRepo:     ledgerRepo,
+		transactor:     transactor,
 	}
 }
 
 func (u *investmentUsecase) Create(ctx context.Context, investment *domain.Investment) error {
-	return u.investmentRepo.Create(ctx, investment)
+	if investment.RiskLevel == "" {
+		investment.RiskLevel = domain.RiskMedium
+	}
+	if investment.Currency == "" {
+		investment.Currency = defaultCurrency
+	}
+	investment.Currency = strings.ToUpper(investment.Currency)
+	investment.Status = domain.InvestmentOpen
+	if investment.NAB <= 0 {
+		investment.NAB = 1
+	}
+	if investment.TotalBalance <= 0 {
+		investment.TotalBalance = 0
+	}
+	if investment.TotalUnits <= 0 {
+		investment.TotalUnits = 0
+	}
+
+	if err := validateInvestment(investment); err != nil {
+		return err
+	}
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.investmentRepo.Create(ctx, investment); err != nil {
+			return err
+		}
+		if err := u.investmentRepo.RecordNAB(ctx, investment.ID, time.Now().Format(utils.DateLayout), investment.NAB); err != nil {
+			return err
+		}
+		err := recordAudit(ctx, u.auditRepo, domain.AuditEntityInvestment, investment.ID, domain.AuditActionInvestmentCreated, "", nil, investment)
+		if err != nil {
+			return err
+		}
+
+		// Seed capital and units the fund starts with come from outside the platform
+		entry := &domain.JournalEntry{Type: domain.EntryOpeningBalance}
+		entry.Transfer(domain.AssetCash, domain.ExternalAccount(), domain.FundCashAccount(investment.ID), investment.TotalBalance)
+		entry.Transfer(domain.AssetUnits, domain.FundUnitsAccount(investment.ID), domain.ExternalAccount(), investment.TotalUnits)
+		if len(entry.Lines) == 0 {
+			return nil
+		}
+		return postEntry(ctx, u.ledgerRepo, entry)
+	})
 }
 
 func (u *investmentUsecase) GetByID(ctx context.Context, id string) (*domain.Investment, error) {
-	return u.investmentRepo.GetByID(ctx, id)
+	investment, err := u.investmentRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvestmentNotFound
+	}
+	return investment, err
 }
 
 func (u *investmentUsecase) GetAll(ctx context.Context) ([]*domain.Investment, error) {
 	return u.investmentRepo.GetAll(ctx)
 }
+
+func (u *investmentUsecase) Update(ctx context.Context, id string, req *domain.UpdateInvestmentRequest) (*domain.Investment, error) {
+	ctx = logger.WithAttrs(ctx, slog.String("investment_id", id))
+	investment, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *investment
+
+	applyString(&investment.Name, req.Name)
+	applyString(&investment.Description, req.Description)
+	applyString(&investment.Manager, req.Manager)
+	applyString(&investment.InceptionDate, req.InceptionDate)
+	if req.Currency != nil {
+		investment.Currency = strings.ToUpper(strings.TrimSpace(*req.Currency))
+	}
+	if req.RiskLevel != nil {
+		investment.RiskLevel = *req.RiskLevel
+	}
+	if req.Category != nil {
+		investment.Category = *req.Category
+	}
+	if req.Rules != nil {
+		investment.Rules = *req.Rules
+	}
+
+	if req.Status != nil && *req.Status != investment.Status {
+		if !req.Status.Valid() {
+			return nil, domain.ErrInvalidInvestmentStatus
+		}
+		if !investment.Status.CanTransitionTo(*req.Status) {
+			return nil, domain.ErrInvalidInvestmentTransition
+		}
+		investment.Status = *req.Status
+	}
+
+	if err := validateInvestment(investment); err != nil {
+		return nil, err
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.investmentRepo.Update(ctx, investment); err != nil {
+			return err
+		}
+		return recordAudit(ctx, u.auditRepo, domain.AuditEntityInvestment, investment.ID, domain.AuditActionInvestmentUpdated, "", &before, investment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return investment, nil
+}
+
+// validateInvestment checks the product metadata of an investment
+func validateInvestment(investment *domain.Investment) error {
+	if strings.TrimSpace(investment.Name) == "" {
+		return domain.ErrInvestmentNameRequired
+	}
+	if !investment.RiskLevel.Valid() {
+		return domain.ErrInvalidRiskLevel
+	}
+	if investment.Category != "" && !investment.Category.Valid() {
+		return domain.ErrInvalidFundCategory
+	}
+	if !currencyPattern.MatchString(investment.Currency) {
+		return domain.ErrInvalidCurrency
+	}
+	if !investment.Rules.Valid() {
+		return domain.ErrInvalidInvestmentRules
+	}
+	if investment.InceptionDate != "" {
+		if _, err := time.Parse(utils.DateLayout, investment.InceptionDate); err != nil {
+			return domain.ErrInvalidInceptionDate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"sort"
+	"time"
+)
+
+type LedgerUsecase interface {
+	GetEntries(ctx context.Context, transactionID, customerID string) ([]*domain.JournalEntry, error)
+	// Verify compares the running totals stored on wallets, holdings and
+	// investments with the balances derived from the journal
+	Verify(ctx context.Context) (*domain.LedgerVerification, error)
+}
+
+type ledgerUsecase struct {
+	ledgerRepo     repository.LedgerRepository
+	investmentRepo repository.InvestmentRepository
+	custInvestRepo repository.CustomerInvestmentRepository
+	walletRepo     repository.WalletRepository
+}
+
+func NewLedgerUsecase(
+	ledgerRepo repository.LedgerRepository,
+	investmentRepo repository.InvestmentRepository,
+	custInvestRepo repository.CustomerInvestmentRepository,
+	walletRepo repository.WalletRepository,
+) LedgerUsecase {
+	return &ledgerUsecase{
+		ledgerRepo:     ledgerRepo,
+		investmentRepo: investmentRepo,
+		custInvestRepo: custInvestRepo,
+		walletRepo:     walletRepo,
+	}
+}
+
+func (u *ledgerUsecase) GetEntries(ctx context.Context, transactionID, customerID string) ([]*domain.JournalEntry, error) {
+	return u.ledgerRepo.GetEntries(ctx, transactionID, customerID)
+}
+
+func (u *ledgerUsecase) Verify(ctx context.Context) (*domain.LedgerVerification, error) {
+	balances, err := u.ledgerRepo.GetBalances(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	stored := []*domain.AccountBalance{}
+
+	wallets, err := u.walletRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, wallet := range wallets {
+		stored = append(stored,
+			&domain.AccountBalance{Account: domain.CustomerCashAccount(wallet.CustomerID), Asset: domain.AssetCash, Balance: wallet.Balance},
+			&domain.AccountBalance{Account: domain.CustomerPendingCashAccount(wallet.CustomerID), Asset: domain.AssetCash, Balance: wallet.PendingBalance})
+	}
+
+	holdings, err := u.custInvestRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, holding := range holdings {
+		stored = append(stored, &domain.AccountBalance{
+			Account: domain.CustomerUnitsAccount(holding.CustomerID, holding.InvestmentID),
+			Asset:   domain.AssetUnits,
+			Balance: holding.Units,
+		})
+	}
+
+	investments, err := u.investmentRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, investment := range investments {
+		stored = append(stored,
+			&domain.AccountBalance{Account: domain.FundCashAccount(investment.ID), Asset: domain.AssetCash, Balance: investment.TotalBalance},
+			// Outstanding units are held as a negative balance on the fund units account
+			&domain.AccountBalance{Account: domain.FundUnitsAccount(investment.ID), Asset: domain.AssetUnits, Balance: -investment.TotalUnits})
+	}
+
+	discrepancies := compareBalances(balances, stored)
+	return &domain.LedgerVerification{
+		Balanced:      len(discrepancies) == 0,
+		Discrepancies: discrepancies,
+		VerifiedAt:    time.Now(),
+	}, nil
+}
+
+type balanceKey struct {
+	account domain.Account
+	asset   domain.Asset
+}
+
+// compareBalances reports every stored balance that differs from the
+// journal, and every journal balance on an account type with stored totals
+// that has no stored counterpart
+func compareBalances(ledger, stored []*domain.AccountBalance) []*domain.LedgerDiscrepancy {
+	ledgerBalances := map[balanceKey]float64{}
+	for _, balance := range ledger {
+		ledgerBalances[balanceKey{balance.Account, balance.Asset}] = balance.Balance
+	}
+
+	discrepancies := []*domain.LedgerDiscrepancy{}
+	seen := map[balanceKey]bool{}
+	for _, balance := range stored {
+		key := balanceKey{balance.Account, balance.Asset}
+		seen[key] = true
+		if d := domain.NewLedgerDiscrepancy(balance.Account, balance.Asset, ledgerBalances[key], balance.Balance); d != nil {
+			discrepancies = append(discrepancies, d)
+		}
+	}
+
+	for key, balance := range ledgerBalances {
+		if seen[key] || key.account.Type == domain.AccountFees || key.account.Type == domain.AccountExternal {
+			continue
+		}
+		if d := domain.NewLedgerDiscrepancy(key.account, key.asset, balance, 0); d != nil {
+			discrepancies = append(discrepancies, d)
+		}
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		a, b := discrepancies[i], discrepancies[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.CustomerID != b.CustomerID {
+			return a.CustomerID < b.CustomerID
+		}
+		return a.InvestmentID < b.InvestmentID
+	})
+
+	return discrepancies
+}
+
+// postEntry checks the entry balances and stores it in the journal
+func postEntry(ctx context.Context, ledgerRepo repository.LedgerRepository, entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	entry.ID = utils.GenerateUUID()
+	entry.CreatedAt = time.Now()
+	return ledgerRepo.Post(ctx, entry)
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"nobi-assesment/internal/domain"
+	"testing"
+)
+
+func TestCompareBalances(t *testing.T) {
+	ledger := []*domain.AccountBalance{
+		{Account: domain.CustomerCashAccount("c1"), Asset: domain.AssetCash, Balance: 100000},
+		{Account: domain.FundCashAccount("i1"), Asset: domain.AssetCash, Balance: 99000},
+		{Account: domain.FundUnitsAccount("i1"), Asset: domain.AssetUnits, Balance: -99},
+		{Account: domain.CustomerUnitsAccount("c2", "i1"), Asset: domain.AssetUnits, Balance: 10},
+		{Account: domain.FeesAccount("i1"), Asset: domain.AssetCash, Balance: 1000},
+		{Account: domain.ExternalAccount(), Asset: domain.AssetCash, Balance: -200000},
+	}
+	stored := []*domain.AccountBalance{
+		{Account: domain.CustomerCashAccount("c1"), Asset: domain.AssetCash, Balance: 100000},
+		{Account: domain.FundCashAccount("i1"), Asset: domain.AssetCash, Balance: 99500},
+		{Account: domain.FundUnitsAccount("i1"), Asset: domain.AssetUnits, Balance: -99},
+		{Account: domain.CustomerPendingCashAccount("c1"), Asset: domain.AssetCash, Balance: 0},
+	}
+
+	got := compareBalances(ledger, stored)
+
+	if len(got) != 2 {
+		t.Fatalf("compareBalances() returned %d discrepancies, want 2: %+v", len(got), got)
+	}
+	if got[0].Type != domain.AccountCustomerUnits || got[0].StoredBalance != 0 || got[0].LedgerBalance != 10 {
+		t.Errorf("compareBalances()[0] = %+v, want holding missing from stored balances", got[0])
+	}
+	if got[1].Type != domain.AccountFundCash || got[1].Difference != 500 {
+		t.Errorf("compareBalances()[1] = %+v, want fund cash off by 500", got[1])
+	}
+}
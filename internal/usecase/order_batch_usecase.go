@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"io"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"strconv"
+	"strings"
+)
+
+type OrderBatchUsecase interface {
+	// Process runs every order of a CSV batch order file through the
+	// transaction usecase, each in its own transaction. Orders whose external
+	// reference was already processed are reported as duplicates.
+	Process(ctx context.Context, r io.Reader) (*domain.OrderBatchReport, error)
+}
+
+type orderBatchUsecase struct {
+	transactionUsecase TransactionUsecase
+	transactionRepo    repository.TransactionRepository
+}
+
+func NewOrderBatchUsecase(transactionUsecase TransactionUsecase, transactionRepo repository.TransactionRepository) OrderBatchUsecase {
+	return &orderBatchUsecase{
+		transactionUsecase: transactionUsecase,
+		transactionRepo:    transactionRepo,
+	}
+}
+
+// orderColumns sets the order field each CSV column holds
+var orderColumns = map[string]func(*domain.BatchOrder, string) error{
+	"customer_id":        func(o *domain.BatchOrder, v string) error { o.CustomerID = v; return nil },
+	"investment_id":      func(o *domain.BatchOrder, v string) error { o.InvestmentID = v; return nil },
+	"type":               func(o *domain.BatchOrder, v string) error { o.Type = strings.ToUpper(v); return nil },
+	"amount":             func(o *domain.BatchOrder, v string) error { return parseAmount(&o.Amount, v) },
+	"units":              func(o *domain.BatchOrder, v string) error { return parseAmount(&o.Units, v) },
+	"external_reference": func(o *domain.BatchOrder, v string) error { o.ExternalReference = v; return nil },
+	"risk_acknowledged": func(o *domain.BatchOrder, v string) error {
+		if v == "" {
+			return nil
+		}
+		acknowledged, err := strconv.ParseBool(v)
+		o.RiskAcknowledged = acknowledged
+		return err
+	},
+}
+
+func (u *orderBatchUsecase) Process(ctx context.Context, r io.Reader) (*domain.OrderBatchReport, error) {
+	next, err := newRecordReader(domain.ImportCSV, r, columnSet(orderColumns))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.OrderBatchReport{Results: []*domain.OrderResult{}}
+	for {
+		record, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result := u.processOrder(ctx, record)
+		report.Total++
+		switch result.Status {
+		case domain.OrderProcessed:
+			report.Processed++
+		case domain.OrderDuplicate:
+			report.Duplicates++
+		default:
+			report.Failed++
+		}
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (u *orderBatchUsecase) processOrder(ctx context.Context, record *importRecord) *domain.OrderResult {
+	result := &domain.OrderResult{Row: record.row, ExternalReference: record.values["external_reference"]}
+
+	order, err := decodeRecord(record, orderColumns)
+	if err == nil {
+		err = validateOrder(order)
+	}
+	if err != nil {
+		return failOrder(result, err)
+	}
+
+	existing, err := u.transactionRepo.GetByExternalReference(ctx, order.ExternalReference)
+	if err == nil {
+		result.Status = domain.OrderDuplicate
+		result.TransactionID = existing.ID
+		result.TransactionStatus = existing.Status
+		return result
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return failOrder(result, err)
+	}
+
+	var response *domain.TransactionResponse
+	if order.Type == "DEPOSIT" {
+		response, err = u.transactionUsecase.Deposit(ctx, &domain.DepositRequest{
+			CustomerID:        order.CustomerID,
+			InvestmentID:      order.InvestmentID,
+			Amount:            order.Amount,
+			RiskAcknowledged:  order.RiskAcknowledged,
+			ExternalReference: order.ExternalReference,
+		})
+	} else {
+		response, err = u.transactionUsecase.Withdraw(ctx, &domain.WithdrawRequest{
+			CustomerID:        order.CustomerID,
+			InvestmentID:      order.InvestmentID,
+			Amount:            order.Amount,
+			Units:             order.Units,
+			ExternalReference: order.ExternalReference,
+		})
+	}
+	if errors.Is(err, domain.ErrDuplicateExternalReference) {
+		// Processed concurrently since the lookup above
+		result.Status = domain.OrderDuplicate
+		return result
+	}
+	if err != nil {
+		return failOrder(result, err)
+	}
+
+	result.Status = domain.OrderProcessed
+	result.TransactionID = response.TransactionID
+	result.TransactionStatus = response.Status
+	return result
+}
+
+// validateOrder checks what the transaction usecase does not: the reference
+// that makes the order idempotent and how much it is for
+func validateOrder(order *domain.BatchOrder) error {
+	if order.ExternalReference == "" {
+		return domain.ErrExternalReferenceRequired
+	}
+
+	switch order.Type {
+	case "DEPOSIT":
+		if order.Amount <= 0 || order.Units != 0 {
+			return domain.ErrInvalidOrderQuantity
+		}
+	case "WITHDRAW":
+		if (order.Amount <= 0) == (order.Units <= 0) {
+			return domain.ErrInvalidOrderQuantity
+		}
+	default:
+		return domain.ErrInvalidOrderType
+	}
+	return nil
+}
+
+func failOrder(result *domain.OrderResult, err error) *domain.OrderResult {
+	result.Status = domain.OrderFailed
+	result.Error = err.Error()
+	if e, ok := domain.AsError(err); ok {
+		result.Code = e.Code
+	}
+	return result
+}
+
+// WriteOrderResults writes the results of a batch as a CSV file, one row per
+// order in the order of the batch file
+func WriteOrderResults(w io.Writer, report *domain.OrderBatchReport) error {
+	records := [][]string{{"row", "external_reference", "status", "transaction_id", "transaction_status", "code", "error"}}
+	for _, result := range report.Results {
+		records = append(records, []string{
+			strconv.Itoa(result.Row),
+			result.ExternalReference,
+			string(result.Status),
+			result.TransactionID,
+			result.TransactionStatus,
+			result.Code,
+			result.Error,
+		})
+	}
+
+	return csv.NewWriter(w).WriteAll(records)
+}
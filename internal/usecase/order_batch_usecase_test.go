@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"errors"
+	"nobi-assesment/internal/domain"
+	"testing"
+)
+
+func TestValidateOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		order domain.BatchOrder
+		want  error
+	}{
+		{"Deposit", domain.BatchOrder{Type: "DEPOSIT", Amount: 100, ExternalReference: "r1"}, nil},
+		{"Withdraw amount", domain.BatchOrder{Type: "WITHDRAW", Amount: 100, ExternalReference: "r2"}, nil},
+		{"Withdraw units", domain.BatchOrder{Type: "WITHDRAW", Units: 10, ExternalReference: "r3"}, nil},
+		{"Missing reference", domain.BatchOrder{Type: "DEPOSIT", Amount: 100}, domain.ErrExternalReferenceRequired},
+		{"Unknown type", domain.BatchOrder{Type: "SWITCH", Amount: 100, ExternalReference: "r4"}, domain.ErrInvalidOrderType},
+		{"Deposit units", domain.BatchOrder{Type: "DEPOSIT", Units: 10, ExternalReference: "r5"}, domain.ErrInvalidOrderQuantity},
+		{"Withdraw both", domain.BatchOrder{Type: "WITHDRAW", Amount: 100, Units: 10, ExternalReference: "r6"}, domain.ErrInvalidOrderQuantity},
+		{"Withdraw neither", domain.BatchOrder{Type: "WITHDRAW", ExternalReference: "r7"}, domain.ErrInvalidOrderQuantity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateOrder(&tt.order); !errors.Is(err, tt.want) {
+				t.Errorf("validateOrder() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/notifier"
+	"nobi-assesment/pkg/utils"
+	"sort"
+	"time"
+)
+
+type ReconciliationUsecase interface {
+	// Run recomputes fund totals and customer holdings from the transaction
+	// history and reports where the stored values differ. With repair the
+	// stored values are moved to the recomputed ones.
+	Run(ctx context.Context, repair bool) (*domain.ReconciliationReport, error)
+}
+
+type reconciliationUsecase struct {
+	investmentRepo  repository.InvestmentRepository
+	custInvestRepo  repository.CustomerInvestmentRepository
+	transactionRepo repository.TransactionRepository
+	ledgerRepo      repository.LedgerRepository
+	transactor      repository.Transactor
+	notifier        notifier.Notifier
+}
+
+func NewReconciliationUsecase(
+	investmentRepo repository.InvestmentRepository,
+	custInvestRepo repository.CustomerInvestmentRepository,
+	transactionRepo repository.TransactionRepository,
+	ledgerRepo repository.LedgerRepository,
+	transactor repository.Transactor,
+	notifier notifier.Notifier,
+) ReconciliationUsecase {
+	return &reconciliationUsecase{
+		investmentRepo:  investmentRepo,
+		custInvestRepo:  custInvestRepo,
+		transactionRepo: transactionRepo,
+		ledgerRepo:      ledgerRepo,
+		transactor:      transactor,
+		notifier:        notifier,
+	}
+}
+
+func (u *reconciliationUsecase) Run(ctx context.Context, repair bool) (*domain.ReconciliationReport, error) {
+	var report *domain.ReconciliationReport
+
+	// Read and repair in one transaction so the repair matches what was read.
+	// Investments and holdings are locked first, in the order orders update
+	// them, so no order can commit between the read and the repair.
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		investments, err := u.investmentRepo.GetAllForUpdate(ctx)
+		if err != nil {
+			return err
+		}
+		holdings, err := u.custInvestRepo.GetAllForUpdate(ctx)
+		if err != nil {
+			return err
+		}
+		positions, err := u.transactionRepo.GetPositions(ctx)
+		if err != nil {
+			return err
+		}
+		openings, err := u.ledgerRepo.GetBalances(ctx, domain.EntryOpeningBalance)
+		if err != nil {
+			return err
+		}
+
+		report = reconcile(investments, holdings, positions, openings)
+		report.ReconciledAt = time.Now()
+		if !repair || report.Balanced {
+			return nil
+		}
+
+		if err := u.repair(ctx, report, holdings); err != nil {
+			return err
+		}
+		report.Repaired = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !report.Balanced {
+		notification := notifier.Notification{
+			Event: "RECONCILIATION_DISCREPANCIES",
+			Message: fmt.Sprintf("Reconciliation found %d investment and %d holding discrepancies",
+				len(report.Investments), len(report.Holdings)),
+			Data: map[string]any{"repaired": report.Repaired},
+			Time: report.ReconciledAt,
+		}
+		if err := u.notifier.Notify(ctx, notification); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// repair moves stored holdings and fund totals to the expected values by
+// the difference found, leaving any change made since the read in place
+func (u *reconciliationUsecase) repair(ctx context.Context, report *domain.ReconciliationReport, holdings []*domain.CustomerInvestment) error {
+	holdingIDs := map[[2]string]string{}
+	for _, holding := range holdings {
+		holdingIDs[[2]string{holding.CustomerID, holding.InvestmentID}] = holding.ID
+	}
+
+	for _, d := range report.Holdings {
+		id, ok := holdingIDs[[2]string{d.CustomerID, d.InvestmentID}]
+		if !ok {
+			err := u.custInvestRepo.Create(ctx, &domain.CustomerInvestment{
+				ID:           utils.GenerateUUID(),
+				CustomerID:   d.CustomerID,
+				InvestmentID: d.InvestmentID,
+				Units:        d.ExpectedUnits,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err := u.custInvestRepo.UpdateUnits(ctx, id, d.ExpectedUnits-d.StoredUnits); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range report.Investments {
+		err := u.investmentRepo.UpdateBalance(ctx, d.InvestmentID,
+			d.ExpectedBalance-d.StoredBalance, d.ExpectedUnits-d.StoredUnits)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconcile compares holdings with the units netted from each customer's
+// transactions, and fund totals with the opening balance plus the net
+// units and cash of every transaction in the fund
+func reconcile(
+	investments []*domain.Investment,
+	holdings []*domain.CustomerInvestment,
+	positions []*domain.TransactionPosition,
+	openings []*domain.AccountBalance,
+) *domain.ReconciliationReport {
+	report := &domain.ReconciliationReport{
+		Investments: []*domain.InvestmentDiscrepancy{},
+		Holdings:    []*domain.HoldingDiscrepancy{},
+	}
+
+	expectedUnits := map[[2]string]float64{}
+	fundUnits := map[string]float64{}
+	fundCash := map[string]float64{}
+	for _, position := range positions {
+		expectedUnits[[2]string{position.CustomerID, position.InvestmentID}] = position.Units
+		fundUnits[position.InvestmentID] += position.Units
+		fundCash[position.InvestmentID] += position.NetCash
+	}
+
+	// Seeded units are held outside the platform, as a negative FUND_UNITS balance
+	for _, opening := range openings {
+		switch opening.Type {
+		case domain.AccountFundCash:
+			fundCash[opening.InvestmentID] += opening.Balance
+		case domain.AccountFundUnits:
+			fundUnits[opening.InvestmentID] -= opening.Balance
+		}
+	}
+
+	checked := map[[2]string]bool{}
+	for _, holding := range holdings {
+		key := [2]string{holding.CustomerID, holding.InvestmentID}
+		checked[key] = true
+		if differs(holding.Units, expectedUnits[key]) {
+			report.Holdings = append(report.Holdings, &domain.HoldingDiscrepancy{
+				CustomerID:    holding.CustomerID,
+				InvestmentID:  holding.InvestmentID,
+				StoredUnits:   holding.Units,
+				ExpectedUnits: expectedUnits[key],
+			})
+		}
+	}
+	// Transactions without a holding row
+	for key, units := range expectedUnits {
+		if checked[key] {
+			continue
+		}
+		checked[key] = true
+		if differs(0, units) {
+			report.Holdings = append(report.Holdings, &domain.HoldingDiscrepancy{
+				CustomerID:    key[0],
+				InvestmentID:  key[1],
+				ExpectedUnits: units,
+			})
+		}
+	}
+
+	for _, investment := range investments {
+		units := fundUnits[investment.ID]
+		balance := fundCash[investment.ID]
+		if differs(investment.TotalUnits, units) || differs(investment.TotalBalance, balance) {
+			report.Investments = append(report.Investments, &domain.InvestmentDiscrepancy{
+				InvestmentID:    investment.ID,
+				Name:            investment.Name,
+				StoredUnits:     investment.TotalUnits,
+				ExpectedUnits:   units,
+				StoredBalance:   investment.TotalBalance,
+				ExpectedBalance: balance,
+			})
+		}
+	}
+
+	sort.Slice(report.Holdings, func(i, j int) bool {
+		a, b := report.Holdings[i], report.Holdings[j]
+		if a.CustomerID != b.CustomerID {
+			return a.CustomerID < b.CustomerID
+		}
+		return a.InvestmentID < b.InvestmentID
+	})
+
+	report.CheckedInvestments = len(investments)
+	report.CheckedHoldings = len(checked)
+	report.Balanced = len(report.Investments) == 0 && len(report.Holdings) == 0
+	return report
+}
+
+func differs(a, b float64) bool {
+	return math.Abs(a-b) > domain.BalanceTolerance
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/notifier"
+	"testing"
+)
+
+func TestReconcile(t *testing.T) {
+	investments := []*domain.Investment{
+		// Seeded with 1000 units for 1000, then c1 bought 100 units and c2 bought 50 and sold 20
+		{ID: "i1", Name: "Balanced", TotalUnits: 1130, TotalBalance: 1130},
+		// Stored totals drifted from the transactions
+		{ID: "i2", Name: "Drifted", TotalUnits: 60, TotalBalance: 60},
+	}
+	holdings := []*domain.CustomerInvestment{
+		{ID: "h1", CustomerID: "c1", InvestmentID: "i1", Units: 100},
+		{ID: "h2", CustomerID: "c2", InvestmentID: "i1", Units: 30},
+		{ID: "h3", CustomerID: "c1", InvestmentID: "i2", Units: 60},
+	}
+	positions := []*domain.TransactionPosition{
+		{CustomerID: "c1", InvestmentID: "i1", Units: 100, NetCash: 100},
+		{CustomerID: "c2", InvestmentID: "i1", Units: 30, NetCash: 30},
+		{CustomerID: "c1", InvestmentID: "i2", Units: 50, NetCash: 50},
+		{CustomerID: "c3", InvestmentID: "i2", Units: 5, NetCash: 5},
+	}
+	openings := []*domain.AccountBalance{
+		{Account: domain.FundCashAccount("i1"), Asset: domain.AssetCash, Balance: 1000},
+		{Account: domain.FundUnitsAccount("i1"), Asset: domain.AssetUnits, Balance: -1000},
+	}
+
+	report := reconcile(investments, holdings, positions, openings)
+
+	if report.Balanced {
+		t.Fatal("reconcile() reported balanced, want discrepancies")
+	}
+	if report.CheckedInvestments != 2 || report.CheckedHoldings != 4 {
+		t.Errorf("reconcile() checked %d investments and %d holdings, want 2 and 4", report.CheckedInvestments, report.CheckedHoldings)
+	}
+
+	if len(report.Investments) != 1 {
+		t.Fatalf("reconcile() returned %d investment discrepancies, want 1", len(report.Investments))
+	}
+	if d := report.Investments[0]; d.InvestmentID != "i2" || d.ExpectedUnits != 55 || d.ExpectedBalance != 55 {
+		t.Errorf("reconcile() investment discrepancy = %+v, want i2 expecting 55 units and 55 balance", d)
+	}
+
+	if len(report.Holdings) != 2 {
+		t.Fatalf("reconcile() returned %d holding discrepancies, want 2", len(report.Holdings))
+	}
+	if d := report.Holdings[0]; d.CustomerID != "c1" || d.StoredUnits != 60 || d.ExpectedUnits != 50 {
+		t.Errorf("reconcile() holding discrepancy = %+v, want c1 storing 60 and expecting 50", d)
+	}
+	if d := report.Holdings[1]; d.CustomerID != "c3" || d.StoredUnits != 0 || d.ExpectedUnits != 5 {
+		t.Errorf("reconcile() holding discrepancy = %+v, want missing c3 holding expecting 5", d)
+	}
+}
+
+type fakeReconcileInvestments struct {
+	repository.InvestmentRepository
+	investments map[string]*domain.Investment
+	locked      bool
+}
+
+func (r *fakeReconcileInvestments) GetAllForUpdate(ctx context.Context) ([]*domain.Investment, error) {
+	r.locked = true
+	investments := []*domain.Investment{}
+	for _, investment := range r.investments {
+		copied := *investment
+		investments = append(investments, &copied)
+	}
+	return investments, nil
+}
+
+func (r *fakeReconcileInvestments) UpdateBalance(ctx context.Context, id string, amountChange, unitsChange float64) error {
+	r.investments[id].TotalBalance += amountChange
+	r.investments[id].TotalUnits += unitsChange
+	return nil
+}
+
+type fakeReconcileHoldings struct {
+	repository.CustomerInvestmentRepository
+	holdings map[string]*domain.CustomerInvestment
+	locked   bool
+}
+
+func (r *fakeReconcileHoldings) GetAllForUpdate(ctx context.Context) ([]*domain.CustomerInvestment, error) {
+	r.locked = true
+	holdings := []*domain.CustomerInvestment{}
+	for _, holding := range r.holdings {
+		copied := *holding
+		holdings = append(holdings, &copied)
+	}
+	return holdings, nil
+}
+
+func (r *fakeReconcileHoldings) UpdateUnits(ctx context.Context, id string, unitsChange float64) error {
+	r.holdings[id].Units += unitsChange
+	return nil
+}
+
+// fakeReconcilePositions returns the positions, then runs onRead as if an
+// order committed between the read and the repair
+type fakeReconcilePositions struct {
+	repository.TransactionRepository
+	positions []*domain.TransactionPosition
+	onRead    func()
+}
+
+func (r *fakeReconcilePositions) GetPositions(ctx context.Context) ([]*domain.TransactionPosition, error) {
+	defer r.onRead()
+	return r.positions, nil
+}
+
+type fakeReconcileLedger struct {
+	repository.LedgerRepository
+}
+
+func (fakeReconcileLedger) GetBalances(ctx context.Context, entryType domain.JournalEntryType) ([]*domain.AccountBalance, error) {
+	return nil, nil
+}
+
+func TestRunRepairKeepsConcurrentChanges(t *testing.T) {
+	investments := &fakeReconcileInvestments{investments: map[string]*domain.Investment{
+		"i1": {ID: "i1", TotalUnits: 60, TotalBalance: 60},
+	}}
+	holdings := &fakeReconcileHoldings{holdings: map[string]*domain.CustomerInvestment{
+		"h1": {ID: "h1", CustomerID: "c1", InvestmentID: "i1", Units: 60},
+	}}
+	positions := &fakeReconcilePositions{
+		positions: []*domain.TransactionPosition{
+			{CustomerID: "c1", InvestmentID: "i1", Units: 50, NetCash: 50},
+		},
+		// A deposit of 10 units for 10 lands after the read
+		onRead: func() {
+			holdings.holdings["h1"].Units += 10
+			investments.investments["i1"].TotalUnits += 10
+			investments.investments["i1"].TotalBalance += 10
+		},
+	}
+
+	u := NewReconciliationUsecase(investments, holdings, positions, fakeReconcileLedger{}, fakeTransactor{}, notifier.NewLogNotifier())
+	report, err := u.Run(context.Background(), true)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if !investments.locked || !holdings.locked {
+		t.Error("Run() read investments and holdings without locking them")
+	}
+	if !report.Repaired {
+		t.Fatal("Run() did not repair the discrepancies")
+	}
+	if units := holdings.holdings["h1"].Units; units != 60 {
+		t.Errorf("holding units = %v after repair, want 60 keeping the deposit", units)
+	}
+	if i := investments.investments["i1"]; i.TotalUnits != 60 || i.TotalBalance != 60 {
+		t.Errorf("investment totals = %v units and %v balance after repair, want 60 and 60 keeping the deposit", i.TotalUnits, i.TotalBalance)
+	}
+}
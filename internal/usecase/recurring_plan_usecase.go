@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/notifier"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+// RecurringPlanConfig controls how due recurring plans are executed
+type RecurringPlanConfig struct {
+	MaxAttempts int           // Attempts per scheduled date before the run is marked failed
+	RetryDelay  time.Duration // Delay before the first retry, doubled on every further attempt
+	BatchSize   int           // Plans picked up per RunDue call
+	LockTTL     time.Duration // How long a worker holds a plan while running it
+}
+
+type RecurringPlanUsecase interface {
+	Create(ctx context.Context, req *domain.CreateRecurringPlanRequest) (*domain.RecurringPlan, error)
+	GetByID(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	GetAll(ctx context.Context, customerID string) ([]*domain.RecurringPlan, error)
+	Update(ctx context.Context, id string, req *domain.UpdateRecurringPlanRequest) (*domain.RecurringPlan, error)
+	Cancel(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	Pause(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	Resume(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	Skip(ctx context.Context, id string) (*domain.RecurringPlan, error)
+	GetRuns(ctx context.Context, id string) ([]*domain.RecurringPlanRun, error)
+	// RunDue deposits for every plan due at now and returns how many plans were processed
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type recurringPlanUsecase struct {
+	planRepo           repository.RecurringPlanRepository
+	customerRepo       repository.CustomerRepository
+	investmentRepo     repository.InvestmentRepository
+	transactionUsecase TransactionUsecase
+	transactor         repository.Transactor
+	notifier           notifier.Notifier
+	config             RecurringPlanConfig
+}
+
+func NewRecurringPlanUsecase(
+	planRepo repository.RecurringPlanRepository,
+	customerRepo repository.CustomerRepository,
+	investmentRepo repository.InvestmentRepository,
+	transactionUsecase TransactionUsecase,
+	transactor repository.Transactor,
+	notifier notifier.Notifier,
+	config RecurringPlanConfig,
+) RecurringPlanUsecase {
+	return &recurringPlanUsecase{
+		planRepo:           planRepo,
+		customerRepo:       customerRepo,
+		investmentRepo:     investmentRepo,
+		transactionUsecase: transactionUsecase,
+		transactor:         transactor,
+		notifier:           notifier,
+		config:             config,
+	}
+}
+
+func (u *recurringPlanUsecase) Create(ctx context.Context, req *domain.CreateRecurringPlanRequest) (*domain.RecurringPlan, error) {
+	if req.CustomerID == "" || req.InvestmentID == "" {
+		return nil, domain.NewError(domain.KindInvalid, "INVALID_PARAMETERS", "customer_id and investment_id are required")
+	}
+	if _, err := u.customerRepo.GetByID(ctx, req.CustomerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+	if _, err := u.investmentRepo.GetByID(ctx, req.InvestmentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvestmentNotFound
+		}
+		return nil, err
+	}
+
+	today := time.Now().Format(utils.DateLayout)
+	if req.StartDate == "" {
+		req.StartDate = today
+	}
+
+	plan := &domain.RecurringPlan{
+		ID:               utils.GenerateUUID(),
+		CustomerID:       req.CustomerID,
+		InvestmentID:     req.InvestmentID,
+		Amount:           req.Amount,
+		Frequency:        req.Frequency,
+		StartDate:        req.StartDate,
+		EndDate:          req.EndDate,
+		NextRunDate:      req.StartDate,
+		Status:           domain.PlanActive,
+		RiskAcknowledged: req.RiskAcknowledged,
+	}
+	if err := validatePlan(plan); err != nil {
+		return nil, err
+	}
+	if plan.StartDate < today {
+		return nil, domain.ErrInvalidPlanDates.WithMessage("start date cannot be in the past")
+	}
+
+	if err := u.planRepo.Create(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return u.planRepo.GetByID(ctx, plan.ID)
+}
+
+func (u *recurringPlanUsecase) GetByID(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	plan, err := u.planRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRecurringPlanNotFound
+	}
+	return plan, err
+}
+
+func (u *recurringPlanUsecase) GetAll(ctx context.Context, customerID string) ([]*domain.RecurringPlan, error) {
+	return u.planRepo.GetAll(ctx, customerID)
+}
+
+func (u *recurringPlanUsecase) Update(ctx context.Context, id string, req *domain.UpdateRecurringPlanRequest) (*domain.RecurringPlan, error) {
+	return u.modify(ctx, id, func(ctx context.Context, plan *domain.RecurringPlan) error {
+		if plan.Status == domain.PlanCompleted || plan.Status == domain.PlanCancelled {
+			return domain.ErrInvalidPlanTransition.WithMessage("finished recurring plans cannot be changed")
+		}
+
+		if req.Amount != nil {
+			plan.Amount = *req.Amount
+		}
+		if req.Frequency != nil {
+			plan.Frequency = *req.Frequency
+		}
+		applyString(&plan.EndDate, req.EndDate)
+
+		return validatePlan(plan)
+	})
+}
+
+func (u *recurringPlanUsecase) Cancel(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	return u.modify(ctx, id, func(ctx context.Context, plan *domain.RecurringPlan) error {
+		if plan.Status != domain.PlanActive && plan.Status != domain.PlanPaused {
+			return domain.ErrInvalidPlanTransition
+		}
+
+		plan.Status = domain.PlanCancelled
+		plan.NextRunDate = ""
+		plan.RetryAt = nil
+		return nil
+	})
+}
+
+func (u *recurringPlanUsecase) Pause(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	return u.modify(ctx, id, func(ctx context.Context, plan *domain.RecurringPlan) error {
+		if plan.Status != domain.PlanActive {
+			return domain.ErrInvalidPlanTransition
+		}
+
+		plan.Status = domain.PlanPaused
+		return nil
+	})
+}
+
+func (u *recurringPlanUsecase) Resume(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	return u.modify(ctx, id, func(ctx context.Context, plan *domain.RecurringPlan) error {
+		if plan.Status != domain.PlanPaused {
+			return domain.ErrInvalidPlanTransition
+		}
+
+		// Dates missed while paused are not caught up
+		plan.Status = domain.PlanActive
+		plan.Attempts = 0
+		plan.RetryAt = nil
+		today := dateOf(time.Now())
+		if plan.NextRunDate < today.Format(utils.DateLayout) {
+			advancePlan(plan, today)
+		}
+		return nil
+	})
+}
+
+func (u *recurringPlanUsecase) Skip(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	return u.modify(ctx, id, func(ctx context.Context, plan *domain.RecurringPlan) error {
+		if plan.Status != domain.PlanActive {
+			return domain.ErrRecurringPlanNotActive
+		}
+
+		err := u.planRepo.CreateRun(ctx, &domain.RecurringPlanRun{
+			ID:           utils.GenerateUUID(),
+			PlanID:       plan.ID,
+			ScheduledFor: plan.NextRunDate,
+			Status:       domain.PlanRunSkipped,
+			Attempts:     plan.Attempts,
+		})
+		if err != nil {
+			return err
+		}
+
+		plan.Attempts = 0
+		plan.RetryAt = nil
+		plan.LastError = ""
+		advancePlan(plan, time.Time{})
+		return nil
+	})
+}
+
+// modify changes a plan and saves it while holding its row lock, so changes
+// are made to the current plan. Plans claimed by the worker are refused
+// until their run is over, as the worker saves the plan it ran.
+func (u *recurringPlanUsecase) modify(ctx context.Context, id string, change func(ctx context.Context, plan *domain.RecurringPlan) error) (*domain.RecurringPlan, error) {
+	var plan *domain.RecurringPlan
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = u.planRepo.GetForUpdate(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.ErrRecurringPlanNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if plan.LockedUntil != nil && plan.LockedUntil.After(time.Now()) {
+			return domain.ErrRecurringPlanRunning
+		}
+
+		if err := change(ctx, plan); err != nil {
+			return err
+		}
+		return u.planRepo.Update(ctx, plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (u *recurringPlanUsecase) GetRuns(ctx context.Context, id string) ([]*domain.RecurringPlanRun, error) {
+	if _, err := u.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.planRepo.GetRuns(ctx, id)
+}
+
+// RunDue runs each due plan it can claim. A plan failing does not stop the
+// others; their errors are returned together.
+func (u *recurringPlanUsecase) RunDue(ctx context.Context, now time.Time) (int, error) {
+	today := now.Format(utils.DateLayout)
+	plans, err := u.planRepo.GetDue(ctx, today, now, u.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Claims are stored to the second, and must match when released
+	claimedUntil := now.Add(u.config.LockTTL).Truncate(time.Second)
+
+	processed := 0
+	var errs []error
+	for _, due := range plans {
+		claimed, err := u.planRepo.Claim(ctx, due.ID, now, claimedUntil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("plan %s: %w", due.ID, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := u.runClaimed(ctx, due.ID, now, claimedUntil); err != nil {
+			errs = append(errs, fmt.Errorf("plan %s: %w", due.ID, err))
+			continue
+		}
+		processed++
+	}
+
+	return processed, errors.Join(errs...)
+}
+
+// runClaimed runs a plan the worker claimed, reading it again as it may
+// have changed since it was found due
+func (u *recurringPlanUsecase) runClaimed(ctx context.Context, id string, now, claimedUntil time.Time) error {
+	plan, err := u.planRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !plan.IsDue(now) {
+		_, err := u.planRepo.Release(ctx, plan, claimedUntil)
+		return err
+	}
+	return u.runPlan(ctx, plan, now, claimedUntil)
+}
+
+// errPlanClaimLost undoes a run whose claim expired before it was saved, as
+// another worker may be running the plan by then
+var errPlanClaimLost = errors.New("recurring plan claim expired during its run")
+
+// runPlan deposits for the plan's current scheduled date and moves the plan
+// on to its next date, or schedules a retry when the deposit fails and
+// attempts remain. A deposit is saved together with its run and the plan,
+// and carries a reference of the plan and date, so a date is never
+// deposited twice.
+func (u *recurringPlanUsecase) runPlan(ctx context.Context, plan *domain.RecurringPlan, now, claimedUntil time.Time) error {
+	var depositErr error
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var resp *domain.TransactionResponse
+		resp, depositErr = u.transactionUsecase.Deposit(ctx, &domain.DepositRequest{
+			CustomerID:        plan.CustomerID,
+			InvestmentID:      plan.InvestmentID,
+			Amount:            plan.Amount,
+			RiskAcknowledged:  plan.RiskAcknowledged,
+			ExternalReference: plan.ID + ":" + plan.NextRunDate,
+		})
+		if depositErr != nil {
+			return depositErr
+		}
+
+		err := u.planRepo.CreateRun(ctx, &domain.RecurringPlanRun{
+			ID:            utils.GenerateUUID(),
+			PlanID:        plan.ID,
+			ScheduledFor:  plan.NextRunDate,
+			Status:        domain.PlanRunSucceeded,
+			Attempts:      plan.Attempts + 1,
+			TransactionID: resp.TransactionID,
+		})
+		if err != nil {
+			return err
+		}
+
+		ran := *plan
+		ran.LastRunAt = &now
+		ran.Attempts = 0
+		ran.RetryAt = nil
+		ran.LastError = ""
+		// Dates missed while the worker was down are not caught up
+		advancePlan(&ran, dateOf(now).AddDate(0, 0, 1))
+		return u.release(ctx, &ran, claimedUntil)
+	})
+	if depositErr == nil {
+		// The run was saved, or rolled back along with its deposit
+		return err
+	}
+
+	plan.LastRunAt = &now
+	plan.Attempts++
+	plan.LastError = depositErr.Error()
+	if plan.Attempts < u.config.MaxAttempts {
+		retryAt := now.Add(u.config.RetryDelay * time.Duration(math.Pow(2, float64(plan.Attempts-1))))
+		plan.RetryAt = &retryAt
+		return u.release(ctx, plan, claimedUntil)
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.planRepo.CreateRun(ctx, &domain.RecurringPlanRun{
+			ID:           utils.GenerateUUID(),
+			PlanID:       plan.ID,
+			ScheduledFor: plan.NextRunDate,
+			Status:       domain.PlanRunFailed,
+			Attempts:     plan.Attempts,
+			Error:        plan.LastError,
+		})
+		if err != nil {
+			return err
+		}
+
+		failed := *plan
+		failed.Attempts = 0
+		failed.RetryAt = nil
+		advancePlan(&failed, dateOf(now).AddDate(0, 0, 1))
+		return u.release(ctx, &failed, claimedUntil)
+	})
+	if err != nil {
+		return err
+	}
+
+	notification := notifier.Notification{
+		Event:      "RECURRING_PLAN_RUN_FAILED",
+		CustomerID: plan.CustomerID,
+		Message: fmt.Sprintf("Recurring deposit of %.2f scheduled for %s failed after %d attempts: %s",
+			plan.Amount, plan.NextRunDate, plan.Attempts, plan.LastError),
+		Data: map[string]any{
+			"plan_id":       plan.ID,
+			"investment_id": plan.InvestmentID,
+			"scheduled_for": plan.NextRunDate,
+		},
+		Time: now,
+	}
+	if err := u.notifier.Notify(ctx, notification); err != nil {
+		slog.WarnContext(ctx, "failed to send notification", "plan_id", plan.ID, "error", err)
+	}
+	return nil
+}
+
+// release saves the plan the worker ran and releases its claim
+func (u *recurringPlanUsecase) release(ctx context.Context, plan *domain.RecurringPlan, claimedUntil time.Time) error {
+	released, err := u.planRepo.Release(ctx, plan, claimedUntil)
+	if err != nil {
+		return err
+	}
+	if !released {
+		return errPlanClaimLost
+	}
+	return nil
+}
+
+// advancePlan moves NextRunDate to the first scheduled date after the
+// current one that is not before notBefore. Plans running past their end
+// date are completed.
+func advancePlan(plan *domain.RecurringPlan, notBefore time.Time) {
+	start, _ := time.Parse(utils.DateLayout, plan.StartDate)
+	next, _ := time.Parse(utils.DateLayout, plan.NextRunDate)
+
+	next = domain.NextScheduledDate(plan.Frequency, start, next)
+	for next.Before(notBefore) {
+		next = domain.NextScheduledDate(plan.Frequency, start, next)
+	}
+
+	if plan.EndDate != "" && next.Format(utils.DateLayout) > plan.EndDate {
+		plan.Status = domain.PlanCompleted
+		plan.NextRunDate = ""
+		return
+	}
+	plan.NextRunDate = next.Format(utils.DateLayout)
+}
+
+// dateOf truncates t to its calendar date
+func dateOf(t time.Time) time.Time {
+	date, _ := time.Parse(utils.DateLayout, t.Format(utils.DateLayout))
+	return date
+}
+
+// validatePlan checks the amount, frequency and dates of a plan
+func validatePlan(plan *domain.RecurringPlan) error {
+	if plan.Amount <= 0 {
+		return domain.ErrInvalidPlanAmount
+	}
+	if !plan.Frequency.Valid() {
+		return domain.ErrInvalidPlanFrequency
+	}
+	if _, err := time.Parse(utils.DateLayout, plan.StartDate); err != nil {
+		return domain.ErrInvalidPlanDates
+	}
+	if plan.EndDate != "" {
+		if _, err := time.Parse(utils.DateLayout, plan.EndDate); err != nil || plan.EndDate < plan.StartDate {
+			return domain.ErrInvalidPlanDates
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/notifier"
+	"testing"
+	"time"
+)
+
+func TestAdvancePlan(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	tests := []struct {
+		name      string
+		plan      domain.RecurringPlan
+		notBefore time.Time
+		wantNext  string
+		wantState domain.PlanStatus
+	}{
+		{
+			name:      "Moves to the following month",
+			plan:      domain.RecurringPlan{Frequency: domain.FrequencyMonthly, StartDate: "2026-01-31", NextRunDate: "2026-01-31", Status: domain.PlanActive},
+			notBefore: date("2026-02-01"),
+			wantNext:  "2026-02-28",
+			wantState: domain.PlanActive,
+		},
+		{
+			name:      "Skips dates missed before notBefore",
+			plan:      domain.RecurringPlan{Frequency: domain.FrequencyWeekly, StartDate: "2026-01-01", NextRunDate: "2026-01-01", Status: domain.PlanActive},
+			notBefore: date("2026-01-20"),
+			wantNext:  "2026-01-22",
+			wantState: domain.PlanActive,
+		},
+		{
+			name:      "Completes after the end date",
+			plan:      domain.RecurringPlan{Frequency: domain.FrequencyMonthly, StartDate: "2026-01-15", EndDate: "2026-03-01", NextRunDate: "2026-02-15", Status: domain.PlanActive},
+			notBefore: date("2026-02-16"),
+			wantNext:  "",
+			wantState: domain.PlanCompleted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := tt.plan
+			advancePlan(&plan, tt.notBefore)
+			if plan.NextRunDate != tt.wantNext || plan.Status != tt.wantState {
+				t.Errorf("advancePlan() = (%q, %s), want (%q, %s)", plan.NextRunDate, plan.Status, tt.wantNext, tt.wantState)
+			}
+		})
+	}
+}
+
+// fakePlanRepository keeps plans in memory, failing to claim the plans in
+// claimErrs
+type fakePlanRepository struct {
+	repository.RecurringPlanRepository
+	plans     map[string]*domain.RecurringPlan
+	claimErrs map[string]error
+	runs      []*domain.RecurringPlanRun
+}
+
+func (r *fakePlanRepository) GetDue(ctx context.Context, today string, now time.Time, limit int) ([]*domain.RecurringPlan, error) {
+	var due []*domain.RecurringPlan
+	for _, id := range []string{"p1", "p2"} {
+		plan := *r.plans[id]
+		due = append(due, &plan)
+	}
+	return due, nil
+}
+
+func (r *fakePlanRepository) GetByID(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	plan := *r.plans[id]
+	return &plan, nil
+}
+
+func (r *fakePlanRepository) GetForUpdate(ctx context.Context, id string) (*domain.RecurringPlan, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *fakePlanRepository) Claim(ctx context.Context, id string, now, until time.Time) (bool, error) {
+	if err := r.claimErrs[id]; err != nil {
+		return false, err
+	}
+	r.plans[id].LockedUntil = &until
+	return true, nil
+}
+
+func (r *fakePlanRepository) Update(ctx context.Context, plan *domain.RecurringPlan) error {
+	saved := *plan
+	r.plans[plan.ID] = &saved
+	return nil
+}
+
+func (r *fakePlanRepository) Release(ctx context.Context, plan *domain.RecurringPlan, claimedUntil time.Time) (bool, error) {
+	if current := r.plans[plan.ID].LockedUntil; current == nil || !current.Equal(claimedUntil) {
+		return false, nil
+	}
+	saved := *plan
+	saved.LockedUntil = nil
+	r.plans[plan.ID] = &saved
+	return true, nil
+}
+
+func (r *fakePlanRepository) CreateRun(ctx context.Context, run *domain.RecurringPlanRun) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+type fakeDepositor struct {
+	TransactionUsecase
+	deposits []*domain.DepositRequest
+}
+
+func (d *fakeDepositor) Deposit(ctx context.Context, req *domain.DepositRequest) (*domain.TransactionResponse, error) {
+	d.deposits = append(d.deposits, req)
+	return &domain.TransactionResponse{TransactionID: "t1"}, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestRunDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	plan := func(id string) *domain.RecurringPlan {
+		return &domain.RecurringPlan{ID: id, Amount: 100, Frequency: domain.FrequencyDaily, StartDate: "2026-03-01", NextRunDate: "2026-03-10", Status: domain.PlanActive}
+	}
+	repo := &fakePlanRepository{
+		plans:     map[string]*domain.RecurringPlan{"p1": plan("p1"), "p2": plan("p2")},
+		claimErrs: map[string]error{"p1": errors.New("connection reset")},
+	}
+	depositor := &fakeDepositor{}
+	u := NewRecurringPlanUsecase(repo, nil, nil, depositor, fakeTransactor{}, notifier.NewLogNotifier(),
+		RecurringPlanConfig{MaxAttempts: 3, RetryDelay: time.Hour, BatchSize: 10, LockTTL: time.Minute})
+
+	// The plan that cannot be claimed does not stop the other
+	processed, err := u.RunDue(context.Background(), now)
+	if processed != 1 || err == nil {
+		t.Fatalf("RunDue() = %d, %v, want 1 and the claim error", processed, err)
+	}
+
+	if len(depositor.deposits) != 1 || depositor.deposits[0].ExternalReference != "p2:2026-03-10" {
+		t.Fatalf("deposits = %+v, want one referencing p2:2026-03-10", depositor.deposits)
+	}
+	if got := repo.plans["p2"]; got.NextRunDate != "2026-03-11" || got.LockedUntil != nil {
+		t.Errorf("plan after run = next %s, locked until %v, want next 2026-03-11 and released", got.NextRunDate, got.LockedUntil)
+	}
+}
+
+func TestModifyClaimedPlan(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Minute)
+	repo := &fakePlanRepository{plans: map[string]*domain.RecurringPlan{
+		"p1": {ID: "p1", Status: domain.PlanActive, NextRunDate: "2026-03-10", LockedUntil: &lockedUntil},
+	}}
+	u := NewRecurringPlanUsecase(repo, nil, nil, nil, fakeTransactor{}, nil, RecurringPlanConfig{})
+
+	if _, err := u.Pause(context.Background(), "p1"); !errors.Is(err, domain.ErrRecurringPlanRunning) {
+		t.Fatalf("Pause() error = %v, want %v", err, domain.ErrRecurringPlanRunning)
+	}
+	if repo.plans["p1"].Status != domain.PlanActive {
+		t.Errorf("Pause() of a running plan saved status %s", repo.plans["p1"].Status)
+	}
+}
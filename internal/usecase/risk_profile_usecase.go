@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+type RiskProfileUsecase interface {
+	GetQuestionnaire(ctx context.Context) []domain.RiskQuestion
+	Submit(ctx context.Context, customerID string, req *domain.RiskProfileRequest) (*domain.CustomerRiskProfile, error)
+	GetCurrent(ctx context.Context, customerID string) (*domain.CustomerRiskProfile, error)
+}
+
+type riskProfileUsecase struct {
+	riskProfileRepo repository.RiskProfileRepository
+	customerRepo    repository.CustomerRepository
+	validity        time.Duration
+}
+
+// NewRiskProfileUsecase creates a risk profile usecase. Profiles expire
+// validity after they are assessed.
+func NewRiskProfileUsecase(
+	riskProfileRepo repository.RiskProfileRepository,
+	customerRepo repository.CustomerRepository,
+	validity time.Duration,
+) RiskProfileUsecase {
+	return &riskProfileUsecase{
+		riskProfileRepo: riskProfileRepo,
+		customerRepo:    customerRepo,
+		validity:        validity,
+	}
+}
+
+func (u *riskProfileUsecase) GetQuestionnaire(ctx context.Context) []domain.RiskQuestion {
+	return domain.RiskQuestionnaire
+}
+
+func (u *riskProfileUsecase) Submit(ctx context.Context, customerID string, req *domain.RiskProfileRequest) (*domain.CustomerRiskProfile, error) {
+	if _, err := u.customerRepo.GetByID(ctx, customerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCustomerNotFound
+		}
+		return nil, err
+	}
+
+	score, profile, err := domain.ScoreRiskAnswers(req.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	riskProfile := &domain.CustomerRiskProfile{
+		ID:         utils.GenerateUUID(),
+		CustomerID: customerID,
+		Profile:    profile,
+		Score:      score,
+		Answers:    req.Answers,
+		AssessedAt: now,
+		ExpiresAt:  now.Add(u.validity),
+	}
+
+	if err := u.riskProfileRepo.Create(ctx, riskProfile); err != nil {
+		return nil, err
+	}
+
+	return riskProfile, nil
+}
+
+func (u *riskProfileUsecase) GetCurrent(ctx context.Context, customerID string) (*domain.CustomerRiskProfile, error) {
+	profile, err := u.riskProfileRepo.GetLatestByCustomer(ctx, customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRiskProfileNotFound
+	}
+	return profile, err
+}
@@ -0,0 +1,291 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"time"
+)
+
+type StatementUsecase interface {
+	// Generate builds a customer's statement for the calendar days from and
+	// to, both formatted as YYYY-MM-DD and inclusive
+	Generate(ctx context.Context, customerID, from, to string) (*domain.Statement, error)
+	// GenerateAll builds the statement of every customer for the period and
+	// hands each to write, stopping at the first error
+	GenerateAll(ctx context.Context, from, to string, write func(*domain.Statement) error) error
+}
+
+type statementUsecase struct {
+	customerRepo    repository.CustomerRepository
+	investmentRepo  repository.InvestmentRepository
+	transactionRepo repository.TransactionRepository
+}
+
+func NewStatementUsecase(
+	customerRepo repository.CustomerRepository,
+	investmentRepo repository.InvestmentRepository,
+	transactionRepo repository.TransactionRepository,
+) StatementUsecase {
+	return &statementUsecase{
+		customerRepo:    customerRepo,
+		investmentRepo:  investmentRepo,
+		transactionRepo: transactionRepo,
+	}
+}
+
+func (u *statementUsecase) Generate(ctx context.Context, customerID, from, to string) (*domain.Statement, error) {
+	start, end, err := statementPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := u.customerRepo.GetByID(ctx, customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCustomerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u.generate(ctx, customer, from, to, start, end)
+}
+
+func (u *statementUsecase) GenerateAll(ctx context.Context, from, to string, write func(*domain.Statement) error) error {
+	start, end, err := statementPeriod(from, to)
+	if err != nil {
+		return err
+	}
+
+	customers, err := u.customerRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, customer := range customers {
+		statement, err := u.generate(ctx, customer, from, to, start, end)
+		if err != nil {
+			return err
+		}
+		if err := write(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *statementUsecase) generate(ctx context.Context, customer *domain.Customer, from, to string, start, end time.Time) (*domain.Statement, error) {
+	history, err := u.transactionRepo.GetHistory(ctx, customer.ID, end)
+	if err != nil {
+		return nil, err
+	}
+	summary := summarizeHistory(history, start)
+
+	statement := &domain.Statement{
+		CustomerID:    customer.ID,
+		CustomerName:  customer.Name,
+		CustomerEmail: customer.Email,
+		From:          from,
+		To:            to,
+		Transactions:  summary.transactions,
+		RealizedGains: summary.gains,
+		GeneratedAt:   time.Now(),
+	}
+
+	if err := u.valueHoldings(ctx, summary.opening, start); err != nil {
+		return nil, err
+	}
+	if err := u.valueHoldings(ctx, summary.closing, end); err != nil {
+		return nil, err
+	}
+	statement.OpeningHoldings = summary.opening
+	statement.ClosingHoldings = summary.closing
+
+	for _, holding := range statement.OpeningHoldings {
+		statement.OpeningValue += holding.Value
+	}
+	for _, holding := range statement.ClosingHoldings {
+		statement.ClosingValue += holding.Value
+	}
+	for _, gain := range statement.RealizedGains {
+		statement.TotalRealizedGain += gain.Gain
+	}
+
+	return statement, nil
+}
+
+// valueHoldings prices holdings at the NAB of the last completed deposit or
+// withdrawal made in their investment before the given time. Investments
+// not traded before then are priced at their current NAB.
+func (u *statementUsecase) valueHoldings(ctx context.Context, holdings []*domain.StatementHolding, before time.Time) error {
+	for _, holding := range holdings {
+		nab, at, err := u.transactionRepo.GetLatestNAB(ctx, holding.InvestmentID, before)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			investment, err := u.investmentRepo.GetByID(ctx, holding.InvestmentID)
+			if err != nil {
+				return err
+			}
+			holding.NAB = investment.NAB
+		case err != nil:
+			return err
+		default:
+			holding.NAB = nab
+			holding.NABDate = at.Format(utils.DateLayout)
+		}
+
+		holding.Value = utils.RoundDown(holding.Units*holding.NAB, 2)
+	}
+
+	return nil
+}
+
+// statementPeriod parses the inclusive calendar days of a statement into the
+// start of the first day and the start of the day after the last
+func statementPeriod(from, to string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(utils.DateLayout, from, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.ErrInvalidStatementPeriod
+	}
+	last, err := time.ParseInLocation(utils.DateLayout, to, time.Local)
+	if err != nil || last.Before(start) {
+		return time.Time{}, time.Time{}, domain.ErrInvalidStatementPeriod
+	}
+
+	return start, last.AddDate(0, 0, 1), nil
+}
+
+type statementSummary struct {
+	opening      []*domain.StatementHolding
+	closing      []*domain.StatementHolding
+	transactions []*domain.Transaction
+	gains        []*domain.RealizedGain
+}
+
+// costPosition tracks the units of one investment and what they cost
+type costPosition struct {
+	investmentID   string
+	investmentName string
+	units          float64
+	cost           float64
+}
+
+// summarizeHistory replays a customer's transactions, oldest first, into the
+// holdings held when the period starts and ends, the transactions made in the
+// period and the gains realized in it. Withdrawals realize the difference
+// between their proceeds and the average cost of the redeemed units. A
+// transaction counts from its own date even if it was reversed later, and its
+// reversal undoes it from the reversal's date.
+func summarizeHistory(history []*domain.Transaction, start time.Time) *statementSummary {
+	summary := &statementSummary{
+		transactions: []*domain.Transaction{},
+		gains:        []*domain.RealizedGain{},
+	}
+	positions := map[string]*costPosition{}
+	order := []string{}
+	withdrawals := map[string]*domain.RealizedGain{}
+	opened := false
+
+	for _, transaction := range history {
+		inPeriod := !transaction.TransactionDate.Before(start)
+		if inPeriod && !opened {
+			summary.opening = snapshotHoldings(positions, order)
+			opened = true
+		}
+		if inPeriod {
+			summary.transactions = append(summary.transactions, transaction)
+		}
+
+		position, ok := positions[transaction.InvestmentID]
+		if !ok {
+			position = &costPosition{investmentID: transaction.InvestmentID, investmentName: transaction.InvestmentName}
+			positions[transaction.InvestmentID] = position
+			order = append(order, transaction.InvestmentID)
+		}
+
+		switch transaction.Type {
+		case "DEPOSIT":
+			position.units += transaction.Units
+			position.cost += transaction.Amount
+
+		case "WITHDRAW":
+			var costBasis float64
+			if position.units > 0 {
+				costBasis = position.cost * transaction.Units / position.units
+			}
+			position.units -= transaction.Units
+			position.cost -= costBasis
+
+			proceeds := transaction.Amount - transaction.Fee
+			gain := &domain.RealizedGain{
+				TransactionID:  transaction.ID,
+				InvestmentID:   transaction.InvestmentID,
+				InvestmentName: position.investmentName,
+				Date:           transaction.TransactionDate,
+				Units:          transaction.Units,
+				Proceeds:       proceeds,
+				CostBasis:      utils.RoundDown(costBasis, 2),
+				Gain:           utils.RoundDown(proceeds-costBasis, 2),
+			}
+			withdrawals[transaction.ID] = gain
+			if inPeriod {
+				summary.gains = append(summary.gains, gain)
+			}
+
+		case "REVERSAL":
+			withdrawal, ok := withdrawals[transaction.ReversesID]
+			if !ok {
+				// A reversed deposit takes back its units at what they cost
+				position.units -= transaction.Units
+				position.cost -= transaction.Amount
+				continue
+			}
+
+			position.units += withdrawal.Units
+			position.cost += withdrawal.CostBasis
+			if inPeriod {
+				summary.gains = append(summary.gains, &domain.RealizedGain{
+					TransactionID:  transaction.ID,
+					InvestmentID:   transaction.InvestmentID,
+					InvestmentName: position.investmentName,
+					Date:           transaction.TransactionDate,
+					Units:          -withdrawal.Units,
+					Proceeds:       -withdrawal.Proceeds,
+					CostBasis:      -withdrawal.CostBasis,
+					Gain:           -withdrawal.Gain,
+				})
+			}
+		}
+	}
+
+	if !opened {
+		summary.opening = snapshotHoldings(positions, order)
+	}
+	summary.closing = snapshotHoldings(positions, order)
+
+	return summary
+}
+
+// snapshotHoldings lists the positions still holding units, in the order
+// their investments were first traded
+func snapshotHoldings(positions map[string]*costPosition, order []string) []*domain.StatementHolding {
+	holdings := []*domain.StatementHolding{}
+	for _, investmentID := range order {
+		position := positions[investmentID]
+		if position.units < domain.BalanceTolerance {
+			continue
+		}
+
+		holdings = append(holdings, &domain.StatementHolding{
+			InvestmentID:   position.investmentID,
+			InvestmentName: position.investmentName,
+			Units:          position.units,
+		})
+	}
+
+	return holdings
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"nobi-assesment/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestSummarizeHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.January, d, 10, 0, 0, 0, time.UTC) }
+	history := []*domain.Transaction{
+		{ID: "t1", InvestmentID: "i1", InvestmentName: "Equity", Type: "DEPOSIT", Amount: 1000, Units: 100, TransactionDate: day(1)},
+		{ID: "t2", InvestmentID: "i1", InvestmentName: "Equity", Type: "DEPOSIT", Amount: 1500, Units: 100, TransactionDate: day(2)},
+		// Period starts on the 10th
+		{ID: "t3", InvestmentID: "i1", InvestmentName: "Equity", Type: "WITHDRAW", Amount: 1500, Fee: 10, Units: 100, TransactionDate: day(10)},
+		{ID: "t4", InvestmentID: "i2", InvestmentName: "Bond", Type: "DEPOSIT", Amount: 500, Units: 50, TransactionDate: day(11)},
+		{ID: "t5", InvestmentID: "i2", InvestmentName: "Bond", Type: "REVERSAL", ReversesID: "t4", Amount: 500, Units: 50, TransactionDate: day(12)},
+		{ID: "t6", InvestmentID: "i1", InvestmentName: "Equity", Type: "WITHDRAW", Amount: 1200, Units: 50, TransactionDate: day(13)},
+		{ID: "t7", InvestmentID: "i1", InvestmentName: "Equity", Type: "REVERSAL", ReversesID: "t6", Amount: 1200, Units: 50, TransactionDate: day(14)},
+	}
+
+	summary := summarizeHistory(history, day(10).Add(-time.Hour))
+
+	if len(summary.opening) != 1 || summary.opening[0].InvestmentID != "i1" || summary.opening[0].Units != 200 {
+		t.Errorf("summarizeHistory() opening = %+v, want 200 units of i1", summary.opening)
+	}
+	if len(summary.closing) != 1 || summary.closing[0].InvestmentID != "i1" || summary.closing[0].Units != 100 {
+		t.Errorf("summarizeHistory() closing = %+v, want 100 units of i1", summary.closing)
+	}
+	if len(summary.transactions) != 5 {
+		t.Errorf("summarizeHistory() returned %d transactions, want 5", len(summary.transactions))
+	}
+
+	// Units cost 12.5 on average, so the first withdrawal realizes 1490 - 1250
+	// and the reversed one realizes 1200 - 625 and then its opposite
+	want := []float64{240, 575, -575}
+	if len(summary.gains) != len(want) {
+		t.Fatalf("summarizeHistory() returned %d gains, want %d", len(summary.gains), len(want))
+	}
+	for i, gain := range summary.gains {
+		if gain.Gain != want[i] {
+			t.Errorf("summarizeHistory() gain %d = %v, want %v", i, gain.Gain, want[i])
+		}
+	}
+}
+
+func TestStatementPeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantErr  bool
+	}{
+		{"Single day", "2025-01-31", "2025-01-31", false},
+		{"Month", "2025-01-01", "2025-01-31", false},
+		{"Reversed", "2025-02-01", "2025-01-31", true},
+		{"Invalid date", "2025-01-01", "31-01-2025", true},
+		{"Missing", "", "2025-01-31", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := statementPeriod(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("statementPeriod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && end.Sub(start) < 24*time.Hour {
+				t.Errorf("statementPeriod() = %v, %v, want the end after the last day", start, end)
+			}
+		})
+	}
+}
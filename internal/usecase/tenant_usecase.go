@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"strings"
+)
+
+type TenantUsecase interface {
+	Create(ctx context.Context, req *domain.CreateTenantRequest) (*domain.Tenant, error)
+	GetAll(ctx context.Context) ([]*domain.Tenant, error)
+	GetByID(ctx context.Context, id string) (*domain.Tenant, error)
+	Update(ctx context.Context, id string, req *domain.UpdateTenantRequest) (*domain.Tenant, error)
+	// Resolve returns the tenant a request belongs to: the tenant of its
+	// principal, else the tenant serving the host, else the default tenant.
+	// A principal may not use the host of another tenant.
+	Resolve(ctx context.Context, principalTenantID, host string) (*domain.Tenant, error)
+}
+
+type tenantUsecase struct {
+	tenantRepo repository.TenantRepository
+}
+
+func NewTenantUsecase(tenantRepo repository.TenantRepository) TenantUsecase {
+	return &tenantUsecase{
+		tenantRepo: tenantRepo,
+	}
+}
+
+func (u *tenantUsecase) Create(ctx context.Context, req *domain.CreateTenantRequest) (*domain.Tenant, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if req.Config.EnabledInvestmentIDs == nil {
+		req.Config.EnabledInvestmentIDs = []string{}
+	}
+
+	tenant := &domain.Tenant{
+		ID:     req.ID,
+		Name:   strings.TrimSpace(req.Name),
+		Host:   normalizeHost(req.Host),
+		Config: req.Config,
+	}
+	if err := u.tenantRepo.Create(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	return u.tenantRepo.GetByID(ctx, tenant.ID)
+}
+
+func (u *tenantUsecase) GetAll(ctx context.Context) ([]*domain.Tenant, error) {
+	return u.tenantRepo.GetAll(ctx)
+}
+
+func (u *tenantUsecase) GetByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	tenant, err := u.tenantRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+func (u *tenantUsecase) Update(ctx context.Context, id string, req *domain.UpdateTenantRequest) (*domain.Tenant, error) {
+	tenant, err := u.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, domain.ErrInvalidTenantConfig.WithMessage("name is required")
+		}
+		tenant.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Host != nil {
+		tenant.Host = normalizeHost(*req.Host)
+	}
+	if req.Config != nil {
+		if err := req.Config.Validate(); err != nil {
+			return nil, err
+		}
+		if req.Config.EnabledInvestmentIDs == nil {
+			req.Config.EnabledInvestmentIDs = []string{}
+		}
+		tenant.Config = *req.Config
+	}
+
+	if err := u.tenantRepo.Update(ctx, tenant); err != nil {
+		return nil, err
+	}
+
+	return u.tenantRepo.GetByID(ctx, id)
+}
+
+func (u *tenantUsecase) Resolve(ctx context.Context, principalTenantID, host string) (*domain.Tenant, error) {
+	hostTenant, err := u.tenantRepo.GetByHost(ctx, normalizeHost(host))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	switch {
+	case principalTenantID != "":
+		if hostTenant != nil && hostTenant.ID != principalTenantID {
+			return nil, domain.ErrTenantMismatch
+		}
+		return u.GetByID(ctx, principalTenantID)
+	case hostTenant != nil:
+		return hostTenant, nil
+	default:
+		return u.GetByID(ctx, domain.DefaultTenantID)
+	}
+}
+
+// normalizeHost lowercases a host and drops its port, so tenants match
+// however the host is written
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		return hostname
+	}
+	return host
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"testing"
+)
+
+// fakeTenantRepository finds the tenants by ID and host
+type fakeTenantRepository struct {
+	repository.TenantRepository
+	tenants []*domain.Tenant
+}
+
+func (r *fakeTenantRepository) GetByID(ctx context.Context, id string) (*domain.Tenant, error) {
+	for _, tenant := range r.tenants {
+		if tenant.ID == id {
+			return tenant, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakeTenantRepository) GetByHost(ctx context.Context, host string) (*domain.Tenant, error) {
+	for _, tenant := range r.tenants {
+		if tenant.Host == host {
+			return tenant, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func TestResolveTenant(t *testing.T) {
+	u := NewTenantUsecase(&fakeTenantRepository{tenants: []*domain.Tenant{
+		{ID: domain.DefaultTenantID},
+		{ID: "acme", Host: "invest.acme.test"},
+	}})
+	operator := &domain.Principal{Roles: []string{domain.RoleOperator}}
+	platformAdmin := &domain.Principal{Roles: []string{domain.RolePlatformAdmin}}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		host     string
+		want     string
+		wantErr  error
+	}{
+		{"Claim-less staff on the default host", operator.HomeTenant(), "api.nobi.test", domain.DefaultTenantID, nil},
+		{"Claim-less staff on another tenant's host", operator.HomeTenant(), "invest.acme.test:443", "", domain.ErrTenantMismatch},
+		{"Platform admin on a tenant's host", platformAdmin.HomeTenant(), "invest.acme.test", "acme", nil},
+		{"Own tenant on its host", "acme", "invest.acme.test", "acme", nil},
+		{"Unauthenticated on a tenant's host", "", "INVEST.acme.test", "acme", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := u.Resolve(context.Background(), tt.tenantID, tt.host)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && tenant.ID != tt.want {
+				t.Errorf("Resolve() = %s, want %s", tenant.ID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"fmt"
+	"nobi-assesment/internal/domain"
+)
+
+// orderContext is the state a transaction rule is evaluated against
+type orderContext struct {
+	Rules          domain.InvestmentRules
+	Amount         float64 // Amount of the order being placed
+	HoldingBalance float64 // Value of the customer's holding before the order
+	DailyTotal     float64 // Amount of same type orders already placed today
+	FullRedemption bool    // Withdrawal redeems the whole holding
+}
+
+// transactionRule checks one business rule, returning a domain error when the
+// order violates it
+type transactionRule func(o *orderContext) error
+
+var depositRules = []transactionRule{
+	minSubscriptionRule,
+	maxHoldingRule,
+	dailySubscriptionLimitRule,
+}
+
+var withdrawRules = []transactionRule{
+	minRedemptionRule,
+	minRemainingBalanceRule,
+	dailyRedemptionLimitRule,
+}
+
+// evaluateRules runs rules in order and returns the first violation
+func evaluateRules(rules []transactionRule, o *orderContext) error {
+	for _, rule := range rules {
+		if err := rule(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func minSubscriptionRule(o *orderContext) error {
+	if o.HoldingBalance <= 0 {
+		if min := o.Rules.MinInitialSubscription; min > 0 && o.Amount < min {
+			return domain.ErrBelowMinInitialSubscription.WithMessage(
+				fmt.Sprintf("minimum initial subscription is %.2f", min))
+		}
+		return nil
+	}
+
+	if min := o.Rules.MinSubsequentSubscription; min > 0 && o.Amount < min {
+		return domain.ErrBelowMinSubsequentSubscription.WithMessage(
+			fmt.Sprintf("minimum subsequent subscription is %.2f", min))
+	}
+	return nil
+}
+
+func maxHoldingRule(o *orderContext) error {
+	if max := o.Rules.MaxHoldingPerCustomer; max > 0 && o.HoldingBalance+o.Amount > max {
+		return domain.ErrMaxHoldingExceeded.WithMessage(
+			fmt.Sprintf("maximum holding per customer is %.2f, current holding is %.2f", max, o.HoldingBalance))
+	}
+	return nil
+}
+
+func dailySubscriptionLimitRule(o *orderContext) error {
+	if limit := o.Rules.DailySubscriptionLimit; limit > 0 && o.DailyTotal+o.Amount > limit {
+		return domain.ErrDailySubscriptionLimitExceeded.WithMessage(
+			fmt.Sprintf("daily subscription limit is %.2f, %.2f already subscribed today", limit, o.DailyTotal))
+	}
+	return nil
+}
+
+func minRedemptionRule(o *orderContext) error {
+	// A full redemption is always allowed so small holdings can be closed
+	if o.FullRedemption {
+		return nil
+	}
+	if min := o.Rules.MinRedemption; min > 0 && o.Amount < min {
+		return domain.ErrBelowMinRedemption.WithMessage(
+			fmt.Sprintf("minimum redemption is %.2f", min))
+	}
+	return nil
+}
+
+func minRemainingBalanceRule(o *orderContext) error {
+	if o.FullRedemption {
+		return nil
+	}
+	if min := o.Rules.MinRemainingBalance; min > 0 && o.HoldingBalance-o.Amount < min {
+		return domain.ErrBelowMinRemainingBalance.WithMessage(
+			fmt.Sprintf("remaining balance must be at least %.2f, redeem the full holding instead", min))
+	}
+	return nil
+}
+
+func dailyRedemptionLimitRule(o *orderContext) error {
+	if limit := o.Rules.DailyRedemptionLimit; limit > 0 && o.DailyTotal+o.Amount > limit {
+		return domain.ErrDailyRedemptionLimitExceeded.WithMessage(
+			fmt.Sprintf("daily redemption limit is %.2f, %.2f already redeemed today", limit, o.DailyTotal))
+	}
+	return nil
+}
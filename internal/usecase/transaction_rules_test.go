@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"errors"
+	"nobi-assesment/internal/domain"
+	"testing"
+)
+
+func TestDepositRules(t *testing.T) {
+	rules := domain.InvestmentRules{
+		MinInitialSubscription:    100000,
+		MinSubsequentSubscription: 10000,
+		MaxHoldingPerCustomer:     1000000,
+		DailySubscriptionLimit:    500000,
+	}
+
+	tests := []struct {
+		name  string
+		order orderContext
+		want  error
+	}{
+		{"Initial subscription above minimum", orderContext{Amount: 100000}, nil},
+		{"Initial subscription below minimum", orderContext{Amount: 50000}, domain.ErrBelowMinInitialSubscription},
+		{"Subsequent subscription above minimum", orderContext{Amount: 50000, HoldingBalance: 100000}, nil},
+		{"Subsequent subscription below minimum", orderContext{Amount: 5000, HoldingBalance: 100000}, domain.ErrBelowMinSubsequentSubscription},
+		{"Holding above maximum", orderContext{Amount: 200000, HoldingBalance: 900000}, domain.ErrMaxHoldingExceeded},
+		{"Daily limit reached", orderContext{Amount: 200000, HoldingBalance: 100000, DailyTotal: 400000}, domain.ErrDailySubscriptionLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order.Rules = rules
+			err := evaluateRules(depositRules, &tt.order)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("evaluateRules() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithdrawRules(t *testing.T) {
+	rules := domain.InvestmentRules{
+		MinRedemption:        10000,
+		MinRemainingBalance:  50000,
+		DailyRedemptionLimit: 300000,
+	}
+
+	tests := []struct {
+		name  string
+		order orderContext
+		want  error
+	}{
+		{"Partial redemption", orderContext{Amount: 20000, HoldingBalance: 100000}, nil},
+		{"Below minimum redemption", orderContext{Amount: 5000, HoldingBalance: 100000}, domain.ErrBelowMinRedemption},
+		{"Remaining balance below minimum", orderContext{Amount: 60000, HoldingBalance: 100000}, domain.ErrBelowMinRemainingBalance},
+		{"Full redemption of small holding", orderContext{Amount: 5000, HoldingBalance: 5000, FullRedemption: true}, nil},
+		{"Daily limit reached", orderContext{Amount: 100000, HoldingBalance: 1000000, DailyTotal: 250000}, domain.ErrDailyRedemptionLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order.Rules = rules
+			err := evaluateRules(withdrawRules, &tt.order)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("evaluateRules() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoRulesConfigured(t *testing.T) {
+	order := orderContext{Amount: 1, HoldingBalance: 1}
+	if err := evaluateRules(depositRules, &order); err != nil {
+		t.Errorf("deposit with no rules returned %v", err)
+	}
+	if err := evaluateRules(withdrawRules, &order); err != nil {
+		t.Errorf("withdraw with no rules returned %v", err)
+	}
+}
@@ -4,17 +4,53 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"log/slog"
 	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/metrics"
 	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/logger"
 	"nobi-assesment/pkg/utils"
+	"strings"
 	"time"
 )
 
 type TransactionUsecase interface {
 	Deposit(ctx context.Context, req *domain.DepositRequest) (*domain.TransactionResponse, error)
 	Withdraw(ctx context.Context, req *domain.WithdrawRequest) (*domain.TransactionResponse, error)
+	// Reverse compensates a deposit or withdrawal with a REVERSAL transaction
+	// that restores the units, fund totals and cash it moved
+	Reverse(ctx context.Context, id string, req *domain.ReverseTransactionRequest) (*domain.Transaction, error)
 	GetCustomerTransactions(ctx context.Context, customerID string) ([]*domain.Transaction, error)
+	GetTransaction(ctx context.Context, id string) (*domain.Transaction, error)
 	GetCustomerPortfolio(ctx context.Context, customerID, investmentID string) (*domain.CustomerPortfolio, error)
+	// SettleRedemptions credits the proceeds of withdrawals due for settlement
+	// to the customers' wallets and returns how many were settled
+	SettleRedemptions(ctx context.Context, now time.Time) (int, error)
+}
+
+// TransactionConfig holds the business rules applied to transactions
+type TransactionConfig struct {
+	// KYCDepositThreshold is the deposit amount above which the customer
+	// must be KYC verified. Zero disables the check.
+	KYCDepositThreshold float64
+
+	// AllowRiskAcknowledgement lets customers deposit into investments above
+	// their risk profile by acknowledging the risk. When false such deposits
+	// are rejected outright.
+	AllowRiskAcknowledgement bool
+
+	// SettlementDelay is how long after a withdrawal its proceeds are
+	// credited to the customer's wallet
+	SettlementDelay time.Duration
+
+	// SettlementBatchSize caps the withdrawals settled per run
+	SettlementBatchSize int
+
+	// SubscriptionFeeRate and RedemptionFeeRate are the fractions of the
+	// transaction amount charged as a fee, for example 0.01 for 1%. Tenants
+	// may replace them with rates of their own.
+	SubscriptionFeeRate float64
+	RedemptionFeeRate   float64
 }
 
 type transactionUsecase struct {
@@ -22,7 +58,13 @@ type transactionUsecase struct {
 	customerRepo    repository.CustomerRepository
 	investmentRepo  repository.InvestmentRepository
 	custInvestRepo  repository.CustomerInvestmentRepository
-	db              *sql.DB // For transactions
+	riskProfileRepo repository.RiskProfileRepository
+	walletRepo      repository.WalletRepository
+	ledgerRepo      repository.LedgerRepository
+	tenantRepo      repository.TenantRepository
+	auditRepo       repository.AuditLogRepository
+	transactor      repository.Transactor
+	config          TransactionConfig
 }
 
 func NewTransactionUsecase(
@@ -30,14 +72,26 @@ func NewTransactionUsecase(
 	customerRepo repository.CustomerRepository,
 	investmentRepo repository.InvestmentRepository,
 	custInvestRepo repository.CustomerInvestmentRepository,
-	db *sql.DB,
+	riskProfileRepo repository.RiskProfileRepository,
+	walletRepo repository.WalletRepository,
+	ledgerRepo repository.LedgerRepository,
+	tenantRepo repository.TenantRepository,
+	auditRepo repository.AuditLogRepository,
+	transactor repository.Transactor,
+	config TransactionConfig,
 ) TransactionUsecase {
 	return &transactionUsecase{
 		transactionRepo: transactionRepo,
 		customerRepo:    customerRepo,
 		investmentRepo:  investmentRepo,
 		custInvestRepo:  custInvestRepo,
-		db:              db,
+		riskProfileRepo: riskProfileRepo,
+		walletRepo:      walletRepo,
+		ledgerRepo:      ledgerRepo,
+		tenantRepo:      tenantRepo,
+		auditRepo:       auditRepo,
+		transactor:      transactor,
+		config:          config,
 	}
 }
 
@@ -46,17 +100,27 @@ func (u *transactionUsecase) Deposit(ctx context.Context, req *domain.DepositReq
 		return nil, errors.New("invalid parameters")
 	}
 
-	// Begin transaction
-	tx, err := u.db.BeginTx(ctx, nil)
+	ctx = logger.WithAttrs(ctx, slog.String("customer_id", req.CustomerID), slog.String("investment_id", req.InvestmentID))
+	var response *domain.TransactionResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = u.deposit(ctx, req)
+		return err
+	})
 	if err != nil {
+		logFailure(ctx, "deposit failed", err)
 		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
 
+	metrics.RecordOrder(metrics.OrderDeposit, req.InvestmentID, response.Amount)
+	slog.InfoContext(ctx, "deposit placed",
+		"transaction_id", response.TransactionID,
+		"amount", response.Amount,
+		"units", response.Units)
+	return response, nil
+}
+
+func (u *transactionUsecase) deposit(ctx context.Context, req *domain.DepositRequest) (*domain.TransactionResponse, error) {
 	// Check customer
 	customer, err := u.customerRepo.GetByID(ctx, req.CustomerID)
 	if err != nil {
@@ -65,12 +129,34 @@ func (u *transactionUsecase) Deposit(ctx context.Context, req *domain.DepositReq
 	if !customer.IsActive {
 		return nil, errors.New("customer is not active")
 	}
+	if u.config.KYCDepositThreshold > 0 && req.Amount > u.config.KYCDepositThreshold && customer.KYCStatus != domain.KYCVerified {
+		return nil, domain.ErrKYCRequired
+	}
+
+	// Lock the wallet, which also serializes the customer's orders
+	wallet, err := u.walletRepo.GetForUpdate(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get investment
 	investment, err := u.investmentRepo.GetByID(ctx, req.InvestmentID)
 	if err != nil {
 		return nil, err
 	}
+	if investment.Status != domain.InvestmentOpen {
+		return nil, domain.ErrInvestmentNotOpen
+	}
+	tenant, err := u.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !tenant.Config.EnablesInvestment(investment.ID) {
+		return nil, domain.ErrInvestmentNotEnabled
+	}
+	if err = u.checkSuitability(ctx, req.CustomerID, investment, req.RiskAcknowledged); err != nil {
+		return nil, err
+	}
 
 	// Calculate NAB and new units
 	var currentNAB float64
@@ -79,56 +165,84 @@ func (u *transactionUsecase) Deposit(ctx context.Context, req *domain.DepositReq
 	} else {
 		currentNAB = investment.NAB
 	}
-	newUnits := utils.RoundDown(req.Amount/currentNAB, 4)
+	feeRate, _ := tenant.Config.FeeRates(u.config.SubscriptionFeeRate, u.config.RedemptionFeeRate)
+	fee := utils.RoundDown(req.Amount*feeRate, 2)
+	netAmount := req.Amount - fee
+	newUnits := utils.RoundDown(netAmount/currentNAB, 4)
+
+	// Get the existing holding, if any
+	customerInvestment, err := u.custInvestRepo.GetByCustomerAndInvestment(ctx, req.CustomerID, req.InvestmentID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	var holdingUnits float64
+	if customerInvestment != nil {
+		holdingUnits = customerInvestment.Units
+	}
+
+	// Apply investment rules
+	dailyTotal, err := u.transactionRepo.SumAmountSince(ctx, req.CustomerID, req.InvestmentID, "DEPOSIT", startOfDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	err = evaluateRules(depositRules, &orderContext{
+		Rules:          investment.Rules,
+		Amount:         req.Amount,
+		HoldingBalance: utils.RoundDown(holdingUnits*currentNAB, 2),
+		DailyTotal:     dailyTotal,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if wallet.Balance < req.Amount {
+		return nil, domain.ErrInsufficientFunds
+	}
 
 	// Update investment
-	err = u.investmentRepo.UpdateBalance(ctx, req.InvestmentID, req.Amount, newUnits)
+	now := time.Now()
+	tradeDate := tenant.Config.TradeDate(now).Format(utils.DateLayout)
+	err = u.investmentRepo.UpdateBalance(ctx, req.InvestmentID, netAmount, newUnits)
 	if err != nil {
 		return nil, err
 	}
+	if err = u.investmentRepo.RecordNAB(ctx, req.InvestmentID, tradeDate, currentNAB); err != nil {
+		return nil, err
+	}
 
 	// Update or create customer investment
-	var customerInvestment *domain.CustomerInvestment
-	var totalUnitsAfterDeposit float64
-
-	customerInvestment, err = u.custInvestRepo.GetByCustomerAndInvestment(ctx, req.CustomerID, req.InvestmentID)
-	if err != nil {
-		// Check if it's a "not found" error or empty data rows
-		if err.Error() == "sql: no rows in result set" {
-			// Create new customer investment
-			newCustomerInvestment := &domain.CustomerInvestment{
-				ID:           utils.GenerateUUID(),
-				CustomerID:   req.CustomerID,
-				InvestmentID: req.InvestmentID,
-				Units:        newUnits,
-			}
-			err = u.custInvestRepo.Create(ctx, newCustomerInvestment)
-			if err != nil {
-				return nil, err
-			}
-			totalUnitsAfterDeposit = newUnits
-		} else {
-			return nil, err
-		}
+	if customerInvestment == nil {
+		err = u.custInvestRepo.Create(ctx, &domain.CustomerInvestment{
+			ID:           utils.GenerateUUID(),
+			CustomerID:   req.CustomerID,
+			InvestmentID: req.InvestmentID,
+			Units:        newUnits,
+		})
 	} else {
-		// Update existing customer investment
 		err = u.custInvestRepo.UpdateUnits(ctx, customerInvestment.ID, newUnits)
-		if err != nil {
-			return nil, err
-		}
-		totalUnitsAfterDeposit = customerInvestment.Units + newUnits
 	}
+	if err != nil {
+		return nil, err
+	}
+	totalUnitsAfterDeposit := holdingUnits + newUnits
 
 	// Create transaction record
 	transaction := &domain.Transaction{
-		ID:              utils.GenerateUUID(),
-		CustomerID:      req.CustomerID,
-		InvestmentID:    req.InvestmentID,
-		Type:            "DEPOSIT",
-		Amount:          req.Amount,
-		Units:           newUnits,
-		NAB:             currentNAB,
-		TransactionDate: time.Now(),
+		ID:                utils.GenerateUUID(),
+		CustomerID:        req.CustomerID,
+		InvestmentID:      req.InvestmentID,
+		Type:              "DEPOSIT",
+		Status:            domain.TransactionCompleted,
+		Amount:            req.Amount,
+		Fee:               fee,
+		Units:             newUnits,
+		NAB:               currentNAB,
+		NABDate:           tradeDate,
+		BalanceBefore:     utils.RoundDown(holdingUnits*currentNAB, 2),
+		BalanceAfter:      utils.RoundDown(totalUnitsAfterDeposit*currentNAB, 2),
+		RiskAcknowledged:  req.RiskAcknowledged,
+		TransactionDate:   now,
+		CompletedDate:     &now,
+		ExternalReference: req.ExternalReference,
 	}
 
 	err = u.transactionRepo.Create(ctx, transaction)
@@ -136,24 +250,81 @@ func (u *transactionUsecase) Deposit(ctx context.Context, req *domain.DepositReq
 		return nil, err
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
+	// Pay for the subscription from the wallet
+	walletBalance := wallet.Balance - req.Amount
+	err = moveFunds(ctx, u.walletRepo, &domain.WalletMovement{
+		CustomerID:    req.CustomerID,
+		Type:          domain.MovementSubscription,
+		Amount:        -req.Amount,
+		BalanceAfter:  walletBalance,
+		TransactionID: transaction.ID,
+	}, 0)
+	if err != nil {
 		return nil, err
 	}
 
-	currentBalance := utils.RoundDown(totalUnitsAfterDeposit*currentNAB, 2)
+	entry := &domain.JournalEntry{Type: domain.EntrySubscription, TransactionID: transaction.ID}
+	entry.Transfer(domain.AssetCash, domain.CustomerCashAccount(req.CustomerID), domain.FundCashAccount(req.InvestmentID), netAmount)
+	entry.Transfer(domain.AssetCash, domain.CustomerCashAccount(req.CustomerID), domain.FeesAccount(req.InvestmentID), fee)
+	entry.Transfer(domain.AssetUnits, domain.FundUnitsAccount(req.InvestmentID), domain.CustomerUnitsAccount(req.CustomerID, req.InvestmentID), newUnits)
+	if err = postEntry(ctx, u.ledgerRepo, entry); err != nil {
+		return nil, err
+	}
+	err = recordAudit(ctx, u.auditRepo, domain.AuditEntityTransaction, transaction.ID, domain.AuditActionDepositCreated, "", nil, transaction)
+	if err != nil {
+		return nil, err
+	}
 
 	return &domain.TransactionResponse{
 		TransactionID:  transaction.ID,
 		Message:        "Deposit successful",
 		Amount:         req.Amount,
+		Fee:            fee,
 		Units:          newUnits,
 		NAB:            currentNAB,
 		TotalUnits:     totalUnitsAfterDeposit,
-		CurrentBalance: currentBalance,
+		CurrentBalance: transaction.BalanceAfter,
+		Status:         transaction.Status,
+		WalletBalance:  walletBalance,
 	}, nil
 }
 
+// tenant returns the tenant of the order, whose config replaces the platform's
+func (u *transactionUsecase) tenant(ctx context.Context) (*domain.Tenant, error) {
+	tenant, err := u.tenantRepo.GetByID(ctx, domain.TenantFromContext(ctx))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTenantNotFound
+	}
+	return tenant, err
+}
+
+// checkSuitability verifies the investment's risk level fits the customer's
+// current risk profile, or that the customer acknowledged the mismatch when
+// acknowledgement is allowed.
+func (u *transactionUsecase) checkSuitability(ctx context.Context, customerID string, investment *domain.Investment, acknowledged bool) error {
+	profile, err := u.riskProfileRepo.GetLatestByCustomer(ctx, customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrRiskProfileRequired
+	}
+	if err != nil {
+		return err
+	}
+	if profile.Expired(time.Now()) {
+		return domain.ErrRiskProfileExpired
+	}
+
+	if profile.Profile.Suits(investment.RiskLevel) {
+		return nil
+	}
+	if !u.config.AllowRiskAcknowledgement {
+		return domain.ErrRiskNotSuitable
+	}
+	if !acknowledged {
+		return domain.ErrRiskAcknowledgementRequired
+	}
+	return nil
+}
+
 func (u *transactionUsecase) GetCustomerTransactions(ctx context.Context, customerID string) ([]*domain.Transaction, error) {
 	// Verify customer exists
 	_, err := u.customerRepo.GetByID(ctx, customerID)
@@ -164,26 +335,48 @@ func (u *transactionUsecase) GetCustomerTransactions(ctx context.Context, custom
 	return u.transactionRepo.GetByCustomerID(ctx, customerID)
 }
 
+func (u *transactionUsecase) GetTransaction(ctx context.Context, id string) (*domain.Transaction, error) {
+	transaction, err := u.transactionRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
 func (u *transactionUsecase) GetCustomerPortfolio(ctx context.Context, customerID, investmentID string) (*domain.CustomerPortfolio, error) {
 	return u.custInvestRepo.GetCustomerPortfolio(ctx, customerID, investmentID)
 }
 
 func (u *transactionUsecase) Withdraw(ctx context.Context, req *domain.WithdrawRequest) (*domain.TransactionResponse, error) {
-	if req.CustomerID == "" || req.InvestmentID == "" || req.Amount <= 0 {
+	if req.CustomerID == "" || req.InvestmentID == "" || (req.Amount <= 0 && req.Units <= 0 && !req.RedeemAll) {
 		return nil, errors.New("invalid parameters")
 	}
 
-	// Begin transaction
-	tx, err := u.db.BeginTx(ctx, nil)
+	ctx = logger.WithAttrs(ctx, slog.String("customer_id", req.CustomerID), slog.String("investment_id", req.InvestmentID))
+	var response *domain.TransactionResponse
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = u.withdraw(ctx, req)
+		return err
+	})
 	if err != nil {
+		logFailure(ctx, "withdrawal failed", err)
 		return nil, err
 	}
-	defer func() {
-		if err != nil {
-			tx.Rollback()
-		}
-	}()
 
+	metrics.RecordOrder(metrics.OrderWithdraw, req.InvestmentID, response.Amount)
+	slog.InfoContext(ctx, "withdrawal placed",
+		"transaction_id", response.TransactionID,
+		"amount", response.Amount,
+		"units", response.UnitsReduced)
+	return response, nil
+}
+
+func (u *transactionUsecase) withdraw(ctx context.Context, req *domain.WithdrawRequest) (*domain.TransactionResponse, error) {
 	// Check customer
 	customer, err := u.customerRepo.GetByID(ctx, req.CustomerID)
 	if err != nil {
@@ -193,15 +386,23 @@ func (u *transactionUsecase) Withdraw(ctx context.Context, req *domain.WithdrawR
 		return nil, errors.New("customer is not active")
 	}
 
+	// Lock the wallet, which also serializes the customer's orders
+	wallet, err := u.walletRepo.GetForUpdate(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get investment
 	investment, err := u.investmentRepo.GetByID(ctx, req.InvestmentID)
 	if err != nil {
 		return nil, err
 	}
+	if investment.Status == domain.InvestmentSuspended {
+		return nil, domain.ErrInvestmentSuspended
+	}
 
 	// Calculate NAB
 	currentNAB := utils.ValidateNAB(investment.TotalBalance, investment.TotalUnits)
-	withdrawUnits := utils.RoundDown(req.Amount/currentNAB, 4)
 
 	// Get customer investment
 	customerInvestment, err := u.custInvestRepo.GetByCustomerAndInvestment(ctx, req.CustomerID, req.InvestmentID)
@@ -209,16 +410,56 @@ func (u *transactionUsecase) Withdraw(ctx context.Context, req *domain.WithdrawR
 		return nil, err
 	}
 
+	amount := req.Amount
+	withdrawUnits := utils.RoundDown(amount/currentNAB, 4)
+	if req.Units > 0 {
+		withdrawUnits = req.Units
+		amount = utils.RoundDown(withdrawUnits*currentNAB, 2)
+	}
+	if req.RedeemAll {
+		withdrawUnits = customerInvestment.Units
+		amount = utils.RoundDown(withdrawUnits*currentNAB, 2)
+	}
+
 	// Check sufficient balance
 	if withdrawUnits > customerInvestment.Units {
 		return nil, errors.New("insufficient balance for withdrawal")
 	}
 
+	// Apply investment rules
+	dailyTotal, err := u.transactionRepo.SumAmountSince(ctx, req.CustomerID, req.InvestmentID, "WITHDRAW", startOfDay(time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	err = evaluateRules(withdrawRules, &orderContext{
+		Rules:          investment.Rules,
+		Amount:         amount,
+		HoldingBalance: utils.RoundDown(customerInvestment.Units*currentNAB, 2),
+		DailyTotal:     dailyTotal,
+		FullRedemption: withdrawUnits == customerInvestment.Units,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := u.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, feeRate := tenant.Config.FeeRates(u.config.SubscriptionFeeRate, u.config.RedemptionFeeRate)
+	fee := utils.RoundDown(amount*feeRate, 2)
+	proceeds := amount - fee
+
 	// Update investment
-	err = u.investmentRepo.UpdateBalance(ctx, req.InvestmentID, -req.Amount, -withdrawUnits)
+	now := time.Now()
+	tradeDate := tenant.Config.TradeDate(now).Format(utils.DateLayout)
+	err = u.investmentRepo.UpdateBalance(ctx, req.InvestmentID, -amount, -withdrawUnits)
 	if err != nil {
 		return nil, err
 	}
+	if err = u.investmentRepo.RecordNAB(ctx, req.InvestmentID, tradeDate, currentNAB); err != nil {
+		return nil, err
+	}
 
 	// Update customer investment
 	err = u.custInvestRepo.UpdateUnits(ctx, customerInvestment.ID, -withdrawUnits)
@@ -226,16 +467,25 @@ func (u *transactionUsecase) Withdraw(ctx context.Context, req *domain.WithdrawR
 		return nil, err
 	}
 
-	// Create transaction record
+	// Create transaction record, settled into the wallet later
+	remainingUnits := customerInvestment.Units - withdrawUnits
+	settlementDate := now.Add(u.config.SettlementDelay)
 	transaction := &domain.Transaction{
-		ID:              utils.GenerateUUID(),
-		CustomerID:      req.CustomerID,
-		InvestmentID:    req.InvestmentID,
-		Type:            "WITHDRAW",
-		Amount:          req.Amount,
-		Units:           withdrawUnits,
-		NAB:             currentNAB,
-		TransactionDate: time.Now(),
+		ID:                utils.GenerateUUID(),
+		CustomerID:        req.CustomerID,
+		InvestmentID:      req.InvestmentID,
+		Type:              "WITHDRAW",
+		Status:            domain.TransactionPending,
+		Amount:            amount,
+		Fee:               fee,
+		Units:             withdrawUnits,
+		NAB:               currentNAB,
+		NABDate:           tradeDate,
+		BalanceBefore:     utils.RoundDown(customerInvestment.Units*currentNAB, 2),
+		BalanceAfter:      utils.RoundDown(remainingUnits*currentNAB, 2),
+		TransactionDate:   now,
+		SettlementDate:    &settlementDate,
+		ExternalReference: req.ExternalReference,
 	}
 
 	err = u.transactionRepo.Create(ctx, transaction)
@@ -243,21 +493,283 @@ func (u *transactionUsecase) Withdraw(ctx context.Context, req *domain.WithdrawR
 		return nil, err
 	}
 
-	// Commit transaction
-	if err = tx.Commit(); err != nil {
+	// Hold the proceeds as pending cash until settlement
+	err = u.walletRepo.AdjustBalance(ctx, req.CustomerID, 0, proceeds)
+	if err != nil {
 		return nil, err
 	}
 
-	remainingUnits := customerInvestment.Units - withdrawUnits
-	currentBalance := utils.RoundDown(remainingUnits*currentNAB, 2)
+	entry := &domain.JournalEntry{Type: domain.EntryRedemption, TransactionID: transaction.ID}
+	entry.Transfer(domain.AssetCash, domain.FundCashAccount(req.InvestmentID), domain.CustomerPendingCashAccount(req.CustomerID), proceeds)
+	entry.Transfer(domain.AssetCash, domain.FundCashAccount(req.InvestmentID), domain.FeesAccount(req.InvestmentID), fee)
+	entry.Transfer(domain.AssetUnits, domain.CustomerUnitsAccount(req.CustomerID, req.InvestmentID), domain.FundUnitsAccount(req.InvestmentID), withdrawUnits)
+	if err = postEntry(ctx, u.ledgerRepo, entry); err != nil {
+		return nil, err
+	}
+	err = recordAudit(ctx, u.auditRepo, domain.AuditEntityTransaction, transaction.ID, domain.AuditActionWithdrawalCreated, "", nil, transaction)
+	if err != nil {
+		return nil, err
+	}
 
 	return &domain.TransactionResponse{
 		TransactionID:  transaction.ID,
 		Message:        "Withdrawal successful",
-		Amount:         req.Amount,
+		Amount:         amount,
+		Fee:            fee,
 		UnitsReduced:   withdrawUnits,
 		NAB:            currentNAB,
 		RemainingUnits: remainingUnits,
-		CurrentBalance: currentBalance,
+		CurrentBalance: transaction.BalanceAfter,
+		Status:         transaction.Status,
+		WalletBalance:  wallet.Balance,
 	}, nil
 }
+
+func (u *transactionUsecase) Reverse(ctx context.Context, id string, req *domain.ReverseTransactionRequest) (*domain.Transaction, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, domain.ErrReasonRequired
+	}
+
+	original, err := u.transactionRepo.GetByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = logger.WithAttrs(ctx,
+		slog.String("customer_id", original.CustomerID),
+		slog.String("investment_id", original.InvestmentID),
+		slog.String("transaction_id", original.ID))
+	var reversal *domain.Transaction
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		reversal, err = u.reverse(ctx, original.ID, original.CustomerID, reason)
+		return err
+	})
+	if err != nil {
+		logFailure(ctx, "reversal failed", err)
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "transaction reversed", "reversal_id", reversal.ID)
+	return reversal, nil
+}
+
+func (u *transactionUsecase) reverse(ctx context.Context, id, customerID, reason string) (*domain.Transaction, error) {
+	// Lock the wallet before reading the status, settlement takes the same
+	// lock before completing a withdrawal
+	wallet, err := u.walletRepo.GetForUpdate(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := u.transactionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original.Status == domain.TransactionReversed {
+		return nil, domain.ErrTransactionAlreadyReversed
+	}
+	if (original.Type != "DEPOSIT" && original.Type != "WITHDRAW") ||
+		(original.Status != domain.TransactionPending && original.Status != domain.TransactionCompleted) {
+		return nil, domain.ErrTransactionNotReversible
+	}
+
+	customerInvestment, err := u.custInvestRepo.GetByCustomerAndInvestment(ctx, original.CustomerID, original.InvestmentID)
+	if errors.Is(err, sql.ErrNoRows) && original.Type == "DEPOSIT" {
+		return nil, domain.ErrReversalUnitsUnavailable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reversal := &domain.Transaction{
+		ID:              utils.GenerateUUID(),
+		CustomerID:      original.CustomerID,
+		InvestmentID:    original.InvestmentID,
+		Type:            "REVERSAL",
+		Status:          domain.TransactionCompleted,
+		Amount:          original.Amount,
+		Fee:             original.Fee,
+		Units:           original.Units,
+		NAB:             original.NAB,
+		NABDate:         original.NABDate,
+		BalanceBefore:   utils.RoundDown(customerInvestment.Units*original.NAB, 2),
+		TransactionDate: now,
+		CompletedDate:   &now,
+		ReversesID:      original.ID,
+		Reason:          reason,
+	}
+	netAmount := original.Amount - original.Fee
+	entry := &domain.JournalEntry{Type: domain.EntryReversal, TransactionID: reversal.ID}
+	customerCash := domain.CustomerCashAccount(original.CustomerID)
+	customerUnits := domain.CustomerUnitsAccount(original.CustomerID, original.InvestmentID)
+	fundCash := domain.FundCashAccount(original.InvestmentID)
+	fundUnits := domain.FundUnitsAccount(original.InvestmentID)
+	fees := domain.FeesAccount(original.InvestmentID)
+
+	var movement *domain.WalletMovement
+	var pendingChange float64
+	switch original.Type {
+	case "DEPOSIT":
+		// Take back the units and refund the whole amount, fee included
+		if customerInvestment.Units < original.Units {
+			return nil, domain.ErrReversalUnitsUnavailable
+		}
+		if err := u.investmentRepo.UpdateBalance(ctx, original.InvestmentID, -netAmount, -original.Units); err != nil {
+			return nil, err
+		}
+		if err := u.custInvestRepo.UpdateUnits(ctx, customerInvestment.ID, -original.Units); err != nil {
+			return nil, err
+		}
+		reversal.BalanceAfter = utils.RoundDown((customerInvestment.Units-original.Units)*original.NAB, 2)
+
+		entry.Transfer(domain.AssetUnits, customerUnits, fundUnits, original.Units)
+		entry.Transfer(domain.AssetCash, fundCash, customerCash, netAmount)
+		entry.Transfer(domain.AssetCash, fees, customerCash, original.Fee)
+		movement = &domain.WalletMovement{
+			CustomerID:    original.CustomerID,
+			Type:          domain.MovementReversal,
+			Amount:        original.Amount,
+			BalanceAfter:  wallet.Balance + original.Amount,
+			TransactionID: reversal.ID,
+		}
+
+	case "WITHDRAW":
+		// Give back the units and return the proceeds, settled or not, to the fund
+		proceedsAccount := customerCash
+		if original.Status == domain.TransactionPending {
+			proceedsAccount = domain.CustomerPendingCashAccount(original.CustomerID)
+			pendingChange = -netAmount
+		} else if wallet.Balance < netAmount {
+			return nil, domain.ErrInsufficientFunds
+		}
+
+		if err := u.investmentRepo.UpdateBalance(ctx, original.InvestmentID, original.Amount, original.Units); err != nil {
+			return nil, err
+		}
+		if err := u.custInvestRepo.UpdateUnits(ctx, customerInvestment.ID, original.Units); err != nil {
+			return nil, err
+		}
+		reversal.BalanceAfter = utils.RoundDown((customerInvestment.Units+original.Units)*original.NAB, 2)
+
+		entry.Transfer(domain.AssetUnits, fundUnits, customerUnits, original.Units)
+		entry.Transfer(domain.AssetCash, proceedsAccount, fundCash, netAmount)
+		entry.Transfer(domain.AssetCash, fees, fundCash, original.Fee)
+		if original.Status == domain.TransactionCompleted {
+			movement = &domain.WalletMovement{
+				CustomerID:    original.CustomerID,
+				Type:          domain.MovementReversal,
+				Amount:        -netAmount,
+				BalanceAfter:  wallet.Balance - netAmount,
+				TransactionID: reversal.ID,
+			}
+		}
+	}
+
+	reversed, err := u.transactionRepo.MarkReversed(ctx, original.ID, original.Status)
+	if err != nil {
+		return nil, err
+	}
+	if !reversed {
+		return nil, domain.ErrTransactionAlreadyReversed
+	}
+	if err := u.transactionRepo.Create(ctx, reversal); err != nil {
+		return nil, err
+	}
+
+	if movement != nil {
+		err = moveFunds(ctx, u.walletRepo, movement, pendingChange)
+	} else {
+		err = u.walletRepo.AdjustBalance(ctx, original.CustomerID, 0, pendingChange)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := postEntry(ctx, u.ledgerRepo, entry); err != nil {
+		return nil, err
+	}
+
+	after := *original
+	after.Status = domain.TransactionReversed
+	err = recordAudit(ctx, u.auditRepo, domain.AuditEntityTransaction, original.ID, domain.AuditActionTransactionReversed, reason, original, &after)
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+func (u *transactionUsecase) SettleRedemptions(ctx context.Context, now time.Time) (int, error) {
+	transactions, err := u.transactionRepo.GetDueSettlements(ctx, now, u.config.SettlementBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, transaction := range transactions {
+		ctx := logger.WithAttrs(ctx,
+			slog.String("customer_id", transaction.CustomerID),
+			slog.String("investment_id", transaction.InvestmentID),
+			slog.String("transaction_id", transaction.ID))
+		err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Lock the wallet before completing so a concurrent run cannot settle twice
+			wallet, err := u.walletRepo.GetForUpdate(ctx, transaction.CustomerID)
+			if err != nil {
+				return err
+			}
+
+			completed, err := u.transactionRepo.MarkCompleted(ctx, transaction.ID, now)
+			if err != nil || !completed {
+				return err
+			}
+
+			proceeds := transaction.Amount - transaction.Fee
+			err = moveFunds(ctx, u.walletRepo, &domain.WalletMovement{
+				CustomerID:    transaction.CustomerID,
+				Type:          domain.MovementRedemption,
+				Amount:        proceeds,
+				BalanceAfter:  wallet.Balance + proceeds,
+				TransactionID: transaction.ID,
+			}, -proceeds)
+			if err != nil {
+				return err
+			}
+
+			entry := &domain.JournalEntry{Type: domain.EntrySettlement, TransactionID: transaction.ID}
+			entry.Transfer(domain.AssetCash, domain.CustomerPendingCashAccount(transaction.CustomerID), domain.CustomerCashAccount(transaction.CustomerID), proceeds)
+			if err := postEntry(ctx, u.ledgerRepo, entry); err != nil {
+				return err
+			}
+
+			after := *transaction
+			after.Status = domain.TransactionCompleted
+			after.CompletedDate = &now
+			err = recordAudit(ctx, u.auditRepo, domain.AuditEntityTransaction, transaction.ID, domain.AuditActionTransactionSettled, "", transaction, &after)
+			if err != nil {
+				return err
+			}
+
+			settled++
+			slog.DebugContext(ctx, "withdrawal settled", "proceeds", proceeds)
+			return nil
+		})
+		if err != nil {
+			logFailure(ctx, "settlement failed", err)
+			return settled, err
+		}
+	}
+
+	return settled, nil
+}
+
+// startOfDay returns midnight of t's day in t's location
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
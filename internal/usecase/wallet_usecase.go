@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/repository"
+	"nobi-assesment/pkg/utils"
+	"strings"
+	"time"
+)
+
+type WalletUsecase interface {
+	Get(ctx context.Context, customerID string) (*domain.Wallet, error)
+	TopUp(ctx context.Context, customerID string, req *domain.WalletFundsRequest) (*domain.WalletMovement, error)
+	Payout(ctx context.Context, customerID string, req *domain.WalletFundsRequest) (*domain.WalletMovement, error)
+	GetMovements(ctx context.Context, customerID string) ([]*domain.WalletMovement, error)
+}
+
+type walletUsecase struct {
+	walletRepo   repository.WalletRepository
+	customerRepo repository.CustomerRepository
+	ledgerRepo   repository.LedgerRepository
+	transactor   repository.Transactor
+}
+
+func NewWalletUsecase(
+	walletRepo repository.WalletRepository,
+	customerRepo repository.CustomerRepository,
+	ledgerRepo repository.LedgerRepository,
+	transactor repository.Transactor,
+) WalletUsecase {
+	return &walletUsecase{
+		walletRepo:   walletRepo,
+		customerRepo: customerRepo,
+		ledgerRepo:   ledgerRepo,
+		transactor:   transactor,
+	}
+}
+
+// Get returns the customer's wallet. Customers that never funded their
+// wallet get an empty one.
+func (u *walletUsecase) Get(ctx context.Context, customerID string) (*domain.Wallet, error) {
+	if _, err := u.getCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	wallet, err := u.walletRepo.GetByCustomerID(ctx, customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &domain.Wallet{CustomerID: customerID}, nil
+	}
+	return wallet, err
+}
+
+func (u *walletUsecase) TopUp(ctx context.Context, customerID string, req *domain.WalletFundsRequest) (*domain.WalletMovement, error) {
+	if req.Amount <= 0 {
+		return nil, domain.ErrInvalidWalletAmount
+	}
+
+	customer, err := u.getCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if !customer.IsActive {
+		return nil, domain.ErrCustomerNotActive
+	}
+
+	var movement *domain.WalletMovement
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := u.walletRepo.GetForUpdate(ctx, customerID)
+		if err != nil {
+			return err
+		}
+
+		movement = &domain.WalletMovement{
+			CustomerID:   customerID,
+			Type:         domain.MovementTopUp,
+			Amount:       req.Amount,
+			BalanceAfter: wallet.Balance + req.Amount,
+			Reference:    strings.TrimSpace(req.Reference),
+		}
+		if err := moveFunds(ctx, u.walletRepo, movement, 0); err != nil {
+			return err
+		}
+
+		entry := &domain.JournalEntry{Type: domain.EntryTopUp, Reference: movement.Reference}
+		entry.Transfer(domain.AssetCash, domain.ExternalAccount(), domain.CustomerCashAccount(customerID), req.Amount)
+		return postEntry(ctx, u.ledgerRepo, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// Payout sends cash out of the wallet. Inactive customers can still be paid
+// out so their remaining cash is not stuck.
+func (u *walletUsecase) Payout(ctx context.Context, customerID string, req *domain.WalletFundsRequest) (*domain.WalletMovement, error) {
+	if req.Amount <= 0 {
+		return nil, domain.ErrInvalidWalletAmount
+	}
+
+	if _, err := u.getCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	var movement *domain.WalletMovement
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := u.walletRepo.GetForUpdate(ctx, customerID)
+		if err != nil {
+			return err
+		}
+		if wallet.Balance < req.Amount {
+			return domain.ErrInsufficientFunds
+		}
+
+		movement = &domain.WalletMovement{
+			CustomerID:   customerID,
+			Type:         domain.MovementPayout,
+			Amount:       -req.Amount,
+			BalanceAfter: wallet.Balance - req.Amount,
+			Reference:    strings.TrimSpace(req.Reference),
+		}
+		if err := moveFunds(ctx, u.walletRepo, movement, 0); err != nil {
+			return err
+		}
+
+		entry := &domain.JournalEntry{Type: domain.EntryPayout, Reference: movement.Reference}
+		entry.Transfer(domain.AssetCash, domain.CustomerCashAccount(customerID), domain.ExternalAccount(), req.Amount)
+		return postEntry(ctx, u.ledgerRepo, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+func (u *walletUsecase) GetMovements(ctx context.Context, customerID string) ([]*domain.WalletMovement, error) {
+	if _, err := u.getCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	return u.walletRepo.GetMovements(ctx, customerID)
+}
+
+func (u *walletUsecase) getCustomer(ctx context.Context, customerID string) (*domain.Customer, error) {
+	customer, err := u.customerRepo.GetByID(ctx, customerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCustomerNotFound
+	}
+	return customer, err
+}
+
+// moveFunds applies a movement to the wallet balance, shifting pendingChange
+// into or out of the pending balance, and records it in the wallet ledger
+func moveFunds(ctx context.Context, walletRepo repository.WalletRepository, movement *domain.WalletMovement, pendingChange float64) error {
+	if err := walletRepo.AdjustBalance(ctx, movement.CustomerID, movement.Amount, pendingChange); err != nil {
+		return err
+	}
+
+	movement.ID = utils.GenerateUUID()
+	movement.CreatedAt = time.Now()
+	return walletRepo.CreateMovement(ctx, movement)
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"nobi-assesment/internal/usecase"
+	"time"
+)
+
+// ReconciliationJob checks fund totals and holdings against the transaction
+// history, repairing them when repair is set
+func ReconciliationJob(reconciliationUsecase usecase.ReconciliationUsecase, repair bool) Job {
+	return func(ctx context.Context, now time.Time) error {
+		report, err := reconciliationUsecase.Run(ctx, repair)
+		if err != nil {
+			return err
+		}
+		if !report.Balanced {
+			slog.WarnContext(ctx, "reconciliation found discrepancies",
+				"investments", len(report.Investments),
+				"holdings", len(report.Holdings),
+				"repaired", report.Repaired)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"nobi-assesment/internal/usecase"
+	"time"
+)
+
+// RecurringPlanJob deposits for every recurring plan that is due
+func RecurringPlanJob(recurringPlanUsecase usecase.RecurringPlanUsecase) Job {
+	return func(ctx context.Context, now time.Time) error {
+		processed, err := recurringPlanUsecase.RunDue(ctx, now)
+		if processed > 0 {
+			slog.InfoContext(ctx, "processed recurring plans", "count", processed)
+		}
+		return err
+	}
+}
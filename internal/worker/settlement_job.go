@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"nobi-assesment/internal/usecase"
+	"time"
+)
+
+// SettlementJob credits the proceeds of withdrawals due for settlement
+func SettlementJob(transactionUsecase usecase.TransactionUsecase) Job {
+	return func(ctx context.Context, now time.Time) error {
+		settled, err := transactionUsecase.SettleRedemptions(ctx, now)
+		if settled > 0 {
+			slog.InfoContext(ctx, "settled withdrawals", "count", settled)
+		}
+		return err
+	}
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"nobi-assesment/internal/domain"
+	"nobi-assesment/internal/usecase"
+	"nobi-assesment/pkg/logger"
+	"time"
+)
+
+// ForEachTenant runs job once per tenant with a context scoped to it, as
+// repositories only reach the records of one tenant at a time. A tenant
+// failing does not stop the others.
+func ForEachTenant(tenantUsecase usecase.TenantUsecase, job Job) Job {
+	return func(ctx context.Context, now time.Time) error {
+		tenants, err := tenantUsecase.GetAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, tenant := range tenants {
+			tenantCtx := logger.WithAttrs(domain.WithTenant(ctx, tenant.ID), slog.String("tenant_id", tenant.ID))
+			if err := job(tenantCtx, now); err != nil {
+				errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.ID, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"nobi-assesment/pkg/logger"
+	"time"
+)
+
+// Job is a unit of work run periodically by a Runner
+type Job func(ctx context.Context, now time.Time) error
+
+// Runner runs a job immediately and then on every interval until its context
+// is cancelled. Job errors are logged and do not stop the runner.
+type Runner struct {
+	name     string
+	interval time.Duration
+	job      Job
+}
+
+func NewRunner(name string, interval time.Duration, job Job) *Runner {
+	return &Runner{
+		name:     name,
+		interval: interval,
+		job:      job,
+	}
+}
+
+// Start blocks until ctx is cancelled
+func (r *Runner) Start(ctx context.Context) {
+	ctx = logger.WithAttrs(ctx, slog.String("worker", r.name))
+	slog.InfoContext(ctx, "worker started", "interval", r.interval)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.job(ctx, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "worker failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,7 +1,35 @@
 package main
 
-import "nobi-assesment/cmd/api"
+import (
+	"nobi-assesment/cmd/api"
+	"nobi-assesment/cmd/export"
+	"nobi-assesment/cmd/importer"
+	"nobi-assesment/cmd/orders"
+	"nobi-assesment/cmd/reconcile"
+	"nobi-assesment/cmd/statements"
+	"os"
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "reconcile":
+			reconcile.Execute(os.Args[2:])
+			return
+		case "import":
+			importer.Execute(os.Args[2:])
+			return
+		case "orders":
+			orders.Execute(os.Args[2:])
+			return
+		case "export":
+			export.Execute(os.Args[2:])
+			return
+		case "statements":
+			statements.Execute(os.Args[2:])
+			return
+		}
+	}
+
 	api.Execute()
 }
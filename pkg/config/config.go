@@ -0,0 +1,86 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Load reads the .env file into the environment when there is one
+func Load() {
+	if err := godotenv.Load(); err != nil {
+		slog.Warn(".env file not found, using environment variables")
+	}
+}
+
+// Get returns the environment variable or the fallback when it is not set
+func Get(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// Float returns the environment variable parsed as a float with fallback
+func Float(key string, fallback float64) float64 {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("invalid config value, using fallback", "key", key, "value", value, "fallback", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Int returns the environment variable parsed as an integer with fallback
+func Int(key string, fallback int) int {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		slog.Warn("invalid config value, using fallback", "key", key, "value", value, "fallback", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Bool returns the environment variable parsed as a boolean with fallback
+func Bool(key string, fallback bool) bool {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("invalid config value, using fallback", "key", key, "value", value, "fallback", fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// Duration returns the environment variable parsed as a duration such as
+// "90s" with fallback
+func Duration(key string, fallback time.Duration) time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		slog.Warn("invalid config value, using fallback", "key", key, "value", value, "fallback", fallback)
+		return fallback
+	}
+	return parsed
+}
@@ -3,7 +3,8 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"nobi-assesment/pkg/config"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -29,6 +30,17 @@ func NewMySQLConnection(username, password, host, port, dbname string) (*sql.DB,
 		return nil, err
 	}
 
-	log.Println("Successfully connected to MySQL database")
+	slog.Info("connected to database", "host", host, "database", dbname)
 	return db, nil
 }
+
+// NewMySQLConnectionFromEnv connects with the DB_* environment variables
+func NewMySQLConnectionFromEnv() (*sql.DB, error) {
+	return NewMySQLConnection(
+		config.Get("DB_USER", "root"),
+		config.Get("DB_PASSWORD", "29/jSGGz&x0c"),
+		config.Get("DB_HOST", "localhost"),
+		config.Get("DB_PORT", "3306"),
+		config.Get("DB_NAME", "nobi_investment"),
+	)
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestContextAttributes(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(&buf, "json", "debug")
+
+	ctx := WithAttrs(context.Background(), slog.String("request_id", "r1"))
+	ctx = WithAttrs(ctx, slog.String("customer_id", "c1"))
+	log.InfoContext(ctx, "deposit placed", "amount", 1000)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	for key, want := range map[string]any{"msg": "deposit placed", "request_id": "r1", "customer_id": "c1", "amount": float64(1000)} {
+		if record[key] != want {
+			t.Errorf("record[%q] = %v, want %v", key, record[key], want)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		level string
+		want  slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"WARN", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"", slog.LevelInfo},
+		{"verbose", slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		if got := parseLevel(tt.level); got != tt.want {
+			t.Errorf("parseLevel(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
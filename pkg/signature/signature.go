@@ -0,0 +1,44 @@
+// Package signature signs API requests with HMAC-SHA256 so the server can
+// tell they were not altered or replayed
+package signature
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Headers a signed request carries besides its API key
+const (
+	HeaderSignature = "X-Signature"           // Hex HMAC-SHA256 of the payload
+	HeaderTimestamp = "X-Signature-Timestamp" // Unix seconds the request was signed at
+	HeaderNonce     = "X-Signature-Nonce"     // Unique value per request
+)
+
+// Payload is what gets signed: the method, the path with its query, the
+// timestamp, the nonce and the SHA-256 of the body, one per line
+func Payload(method, path, timestamp, nonce string, body []byte) []byte {
+	bodyHash := sha256.Sum256(body)
+	return []byte(strings.Join([]string{
+		strings.ToUpper(method),
+		path,
+		timestamp,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n"))
+}
+
+// Sign returns the signature of a request
+func Sign(secret, method, path, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(Payload(method, path, timestamp, nonce, body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of the request, in
+// constant time
+func Verify(secret, signature, method, path, timestamp, nonce string, body []byte) bool {
+	expected := Sign(secret, method, path, timestamp, nonce, body)
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
@@ -0,0 +1,32 @@
+package signature
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"amount": 1000}`)
+	signed := Sign("secret", "POST", "/api/transactions/deposit", "1700000000", "n1", body)
+
+	tests := []struct {
+		name                 string
+		secret, method, path string
+		timestamp, nonce     string
+		body                 []byte
+		want                 bool
+	}{
+		{"Same request", "secret", "post", "/api/transactions/deposit", "1700000000", "n1", body, true},
+		{"Other secret", "other", "POST", "/api/transactions/deposit", "1700000000", "n1", body, false},
+		{"Other path", "secret", "POST", "/api/transactions/withdraw", "1700000000", "n1", body, false},
+		{"Other timestamp", "secret", "POST", "/api/transactions/deposit", "1700000001", "n1", body, false},
+		{"Other nonce", "secret", "POST", "/api/transactions/deposit", "1700000000", "n2", body, false},
+		{"Other body", "secret", "POST", "/api/transactions/deposit", "1700000000", "n1", []byte(`{"amount": 9000}`), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Verify(tt.secret, signed, tt.method, tt.path, tt.timestamp, tt.nonce, tt.body)
+			if got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"net/mail"
+	"regexp"
+	"time"
+)
+
+// DateLayout is the layout used for calendar dates such as date of birth
+const DateLayout = "2006-01-02"
+
+var (
+	phonePattern    = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+	idNumberPattern = regexp.MustCompile(`^[A-Za-z0-9]{6,32}$`)
+)
+
+// ValidateEmail checks that email is a bare address such as user@example.com
+func ValidateEmail(email string) bool {
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Address == email
+}
+
+// ValidatePhone checks that phone contains 8 to 15 digits with an optional leading +
+func ValidatePhone(phone string) bool {
+	return phonePattern.MatchString(phone)
+}
+
+// ValidateIDNumber checks that an identity document number is 6 to 32 alphanumeric characters
+func ValidateIDNumber(idNumber string) bool {
+	return idNumberPattern.MatchString(idNumber)
+}
+
+// ValidateDateOfBirth checks that dob is a YYYY-MM-DD date in the past
+func ValidateDateOfBirth(dob string) bool {
+	date, err := time.Parse(DateLayout, dob)
+	return err == nil && date.Before(time.Now())
+}
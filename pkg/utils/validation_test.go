@@ -0,0 +1,60 @@
+package utils
+
+import "testing"
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  bool
+	}{
+		{"user@example.com", true},
+		{"first.last+tag@example.co.id", true},
+		{"", false},
+		{"user", false},
+		{"user@", false},
+		{"User <user@example.com>", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidateEmail(tt.email); got != tt.want {
+			t.Errorf("ValidateEmail(%q) = %v, want %v", tt.email, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  bool
+	}{
+		{"+6281234567890", true},
+		{"081234567890", true},
+		{"1234567", false},
+		{"+62 812 3456", false},
+		{"phone", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidatePhone(tt.phone); got != tt.want {
+			t.Errorf("ValidatePhone(%q) = %v, want %v", tt.phone, got, tt.want)
+		}
+	}
+}
+
+func TestValidateDateOfBirth(t *testing.T) {
+	tests := []struct {
+		dob  string
+		want bool
+	}{
+		{"1990-01-31", true},
+		{"1990-02-31", false},
+		{"31-01-1990", false},
+		{"2999-01-01", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidateDateOfBirth(tt.dob); got != tt.want {
+			t.Errorf("ValidateDateOfBirth(%q) = %v, want %v", tt.dob, got, tt.want)
+		}
+	}
+}
@@ -6,14 +6,23 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"nobi-assesment/pkg/signature"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 const ApiURL = "http://localhost:3000"
 
+// JWTSecret signs the tokens of the tests, the JWT_HS256_SECRET of
+// docker-compose.yaml
+const JWTSecret = "nobi-development-secret"
+
 func TestApi(t *testing.T) {
 	testcase := getTestCases()
 	ctx := context.Background()
@@ -27,6 +36,9 @@ func TestApi(t *testing.T) {
 				request.Header.Set("Content-Type", "application/json")
 				request.Header.Set("Accept", "application/json")
 				require.NoError(t, err)
+				if request.Header.Get("Authorization") == "" {
+					request.Header.Set("Authorization", "Bearer "+SignToken(t, ""))
+				}
 
 				// Send request
 				response, err := client.Do(request)
@@ -42,9 +54,110 @@ func TestApi(t *testing.T) {
 	}
 }
 
+func TestConcurrentCustomerCreate(t *testing.T) {
+	const workers = 20
+	client := &http.Client{}
+	body, err := json.Marshal(map[string]string{
+		"name": "user_" + uuid.New().String()[:8],
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	statuses := make(chan int, workers)
+	start := make(chan struct{})
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			request, err := http.NewRequest("POST", ApiURL+"/api/customers", bytes.NewReader(body))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer "+SignToken(t, ""))
+
+			response, err := client.Do(request)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			response.Body.Close()
+			statuses <- response.StatusCode
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	close(statuses)
+
+	created, conflicts := 0, 0
+	for status := range statuses {
+		switch status {
+		case http.StatusCreated:
+			created++
+		case http.StatusConflict:
+			conflicts++
+		default:
+			t.Errorf("unexpected status %d", status)
+		}
+	}
+
+	require.Equal(t, 1, created, "exactly one customer must be created")
+	require.Equal(t, workers-1, conflicts)
+}
+
+func TestAuthentication(t *testing.T) {
+	client := &http.Client{}
+
+	for name, authorization := range map[string]string{
+		"Missing token": "",
+		"Invalid token": "Bearer " + SignToken(t, "")[1:],
+	} {
+		t.Run(name, func(t *testing.T) {
+			request, err := http.NewRequest("GET", ApiURL+"/api/customers", nil)
+			require.NoError(t, err)
+			request.Header.Set("Authorization", authorization)
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			var result map[string]any
+			require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+			require.Equal(t, http.StatusUnauthorized, response.StatusCode)
+			require.Equal(t, "UNAUTHENTICATED", result["code"])
+		})
+	}
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	request, err := http.NewRequest("GET", ApiURL+"/api/investments", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer "+SignToken(t, ""))
+
+	response, err := (&http.Client{}).Do(request)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	require.NotEmpty(t, response.Header.Get("RateLimit-Limit"))
+	require.NotEmpty(t, response.Header.Get("RateLimit-Remaining"))
+}
+
 func getTestCases() []TestCase {
 	id_customer := ""
 	id_investment := ""
+	id_plan := ""
+	id_withdrawal := ""
+	id_limited_investment := ""
+	id_limited_deposit := ""
+	api_key := ""
+	id_api_key := ""
+	signing_secret := ""
 
 	return []TestCase{
 		{
@@ -69,6 +182,44 @@ func getTestCases() []TestCase {
 				},
 			},
 		},
+		{
+			Name: "Test customer can only access their own data and permitted routes",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer, nil)
+						req.Header.Set("Authorization", "Bearer "+SignToken(t, id_customer))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, id_customer, m["id"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer, nil)
+						req.Header.Set("Authorization", "Bearer "+SignToken(t, uuid.NewString()))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusForbidden, r.StatusCode)
+						require.Equal(t, "FORBIDDEN", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/kyc", bytes.NewReader([]byte(`{"status": "VERIFIED"}`)))
+						req.Header.Set("Authorization", "Bearer "+SignToken(t, id_customer))
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusForbidden, r.StatusCode)
+						require.Equal(t, "FORBIDDEN", m["code"])
+					},
+				},
+			},
+		},
 		{
 			Name: "Test to add product investments",
 			Steps: []TestCaseStep{
@@ -90,6 +241,85 @@ func getTestCases() []TestCase {
 				},
 			},
 		},
+		{
+			Name: "Test to submit risk profile",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        1000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, r.StatusCode)
+						require.Equal(t, "RISK_PROFILE_REQUIRED", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						answers := []map[string]string{
+							{"question_id": "horizon", "option_id": "1to3y"},
+							{"question_id": "objective", "option_id": "income"},
+							{"question_id": "drawdown", "option_id": "sell_some"},
+							{"question_id": "experience", "option_id": "fixed_income"},
+							{"question_id": "income_share", "option_id": "10to25"},
+						}
+
+						body, err := json.Marshal(map[string]interface{}{"answers": answers})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/risk-profile", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.Equal(t, "MODERATE", m["profile"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to top up wallet",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        100000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, r.StatusCode)
+						require.Equal(t, "INSUFFICIENT_FUNDS", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]interface{}{"amount": 200000.00, "reference": "TOPUP-1"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/wallet/top-up", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.Equal(t, "TOP_UP", m["type"])
+						require.Equal(t, 200000.00, m["balance_after"])
+					},
+				},
+			},
+		},
 		{
 			Name: "Test to deposit",
 			Steps: []TestCaseStep{
@@ -112,6 +342,7 @@ func getTestCases() []TestCase {
 						t.Log(id_investment)
 						require.Equal(t, http.StatusOK, r.StatusCode)
 						RequireIsUUID(t, m["transaction_id"].(string))
+						require.Equal(t, 100000.00, m["wallet_balance"])
 					},
 				},
 			},
@@ -138,42 +369,765 @@ func getTestCases() []TestCase {
 						t.Log(id_investment)
 						require.Equal(t, http.StatusOK, r.StatusCode)
 						RequireIsUUID(t, m["transaction_id"].(string))
+						require.Equal(t, "PENDING", m["status"])
+						id_withdrawal = m["transaction_id"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer+"/wallet", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, 100000.00, m["balance"])
+						require.Equal(t, 50000.00, m["pending_balance"])
 					},
 				},
 			},
 		},
 		{
-			Name: "Test to get portfolio",
+			Name: "Test to dry run customer import",
 			Steps: []TestCaseStep{
 				{
 					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
-						return http.NewRequest("GET", ApiURL+"/api/portfolio/"+id_customer+"/"+id_investment, nil)
+						body := "name,email\nImport " + uuid.NewString() + ",import@example.com\nImport " + uuid.NewString() + ",not-an-email\n"
+
+						return http.NewRequest("POST", ApiURL+"/api/imports/customers?dry_run=true", bytes.NewReader([]byte(body)))
 					},
 					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
-						t.Log("POST /api/portfolio/" + id_customer + "/" + id_investment)
-						t.Log(id_customer)
-						t.Log(id_investment)
-						t.Log(m)
 						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, true, m["dry_run"])
+						require.Equal(t, 2.0, m["total"])
+						require.Equal(t, 1.0, m["succeeded"])
+						require.Equal(t, 1.0, m["failed"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/imports/wallets", bytes.NewReader([]byte("name\n")))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "INVALID_IMPORT_ENTITY", m["code"])
 					},
 				},
 			},
 		},
-	}
-}
+		{
+			Name: "Test to process batch order file",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body := "customer_id,investment_id,type,amount,external_reference\n" +
+							id_customer + "," + id_investment + ",DEPOSIT,1000,\n" +
+							id_customer + "," + id_investment + ",SWITCH,1000," + uuid.NewString() + "\n"
 
-type TestCase struct {
-	Name  string
-	Steps []TestCaseStep
-}
+						return http.NewRequest("POST", ApiURL+"/api/transactions/batch?format=json", bytes.NewReader([]byte(body)))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, 2.0, m["total"])
+						require.Equal(t, 2.0, m["failed"])
 
-type RequestFunc func(*testing.T, context.Context, *TestCase) (*http.Request, error)
-type ExpectFunc func(*testing.T, context.Context, *TestCase, *http.Response, map[string]any)
+						results := m["results"].([]any)
+						require.Equal(t, "EXTERNAL_REFERENCE_REQUIRED", results[0].(map[string]any)["code"])
+						require.Equal(t, "INVALID_ORDER_TYPE", results[1].(map[string]any)["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to export unknown dataset",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/exports/wallets?format=csv", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "INVALID_EXPORT_DATASET", m["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test API key scoped to a customer",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]any{
+							"name":         "partner_" + uuid.NewString()[:8],
+							"permissions":  []string{"customers:read", "transactions:read"},
+							"customer_ids": []string{id_customer},
+						})
+						require.NoError(t, err)
 
-type TestCaseStep struct {
-	Request RequestFunc
-	Expect  ExpectFunc
-	Result  map[string]any
+						return http.NewRequest("POST", ApiURL+"/api/api-keys", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.NotEmpty(t, m["key"])
+						api_key = m["key"].(string)
+						id_api_key = m["id"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer, nil)
+						req.Header.Set("X-API-Key", api_key)
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/wallet/top-up", bytes.NewReader([]byte(`{"amount": 1000}`)))
+						req.Header.Set("X-API-Key", api_key)
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusForbidden, r.StatusCode)
+						require.Equal(t, "FORBIDDEN", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/api-keys/"+id_api_key+"/revoke", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.NotEmpty(t, m["revoked_at"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer, nil)
+						req.Header.Set("X-API-Key", api_key)
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, r.StatusCode)
+						require.Equal(t, "INVALID_API_KEY", m["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to get transaction",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/transactions/"+id_withdrawal, nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "WITHDRAW", m["type"])
+						require.NotEmpty(t, m["investment_name"])
+						require.NotEmpty(t, m["nab_date"])
+						require.Greater(t, m["balance_before"], m["balance_after"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/transactions/"+uuid.NewString(), nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusNotFound, r.StatusCode)
+						require.Equal(t, "TRANSACTION_NOT_FOUND", m["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test signed API key requests",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]any{
+							"name":              "partner_" + uuid.NewString()[:8],
+							"permissions":       []string{"transactions:read"},
+							"require_signature": true,
+						})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/api-keys", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.NotEmpty(t, m["signing_secret"])
+						api_key = m["key"].(string)
+						signing_secret = m["signing_secret"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/transactions/"+id_withdrawal, nil)
+						req.Header.Set("X-API-Key", api_key)
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnauthorized, r.StatusCode)
+						require.Equal(t, "SIGNATURE_REQUIRED", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req, err := http.NewRequest("GET", ApiURL+"/api/transactions/"+id_withdrawal, nil)
+						SignRequest(req, api_key, signing_secret, nil)
+						return req, err
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, id_withdrawal, m["id"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to generate statement",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer+"/statements?from=2025-01-31&to=2025-01-01", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "INVALID_STATEMENT_PERIOD", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer+"/statements?from=2025-01-01&to=2025-01-31&format=xls", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "INVALID_STATEMENT_FORMAT", m["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to reverse withdrawal",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/transactions/"+id_withdrawal+"/reverse", bytes.NewReader([]byte(`{}`)))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "REASON_REQUIRED", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"reason": "submitted twice"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/"+id_withdrawal+"/reverse", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.Equal(t, "REVERSAL", m["type"])
+						require.Equal(t, id_withdrawal, m["reverses_id"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"reason": "submitted twice"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/"+id_withdrawal+"/reverse", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusConflict, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/customers/"+id_customer+"/wallet", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, 100000.00, m["balance"])
+						require.Equal(t, 0.00, m["pending_balance"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to verify ledger",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/ledger/verify", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Contains(t, m, "balanced")
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to get portfolio",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/portfolio/"+id_customer+"/"+id_investment, nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						t.Log("POST /api/portfolio/" + id_customer + "/" + id_investment)
+						t.Log(id_customer)
+						t.Log(id_investment)
+						t.Log(m)
+						require.Equal(t, http.StatusOK, r.StatusCode)
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to update customer",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]string{
+							"name": "user_" + uuid.New().String()[:8],
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/customers/"+id_customer, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, id_customer, m["id"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to deactivate and reactivate customer",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"reason": "customer request"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/deactivate", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, false, m["is_active"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        1000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/reactivate", bytes.NewReader([]byte(`{}`)))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "REASON_REQUIRED", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"reason": "issue resolved"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/reactivate", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, true, m["is_active"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test to verify customer kyc",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"email": "not-an-email"})
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/customers/"+id_customer, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusBadRequest, r.StatusCode)
+						require.Equal(t, "INVALID_EMAIL", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"status": "PENDING"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/kyc", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, r.StatusCode)
+						require.Equal(t, "KYC_PROFILE_INCOMPLETE", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]string{
+							"email":         "user_" + uuid.New().String()[:8] + "@example.com",
+							"phone":         "+6281234567890",
+							"id_number":     "3171234567890001",
+							"date_of_birth": "1990-01-31",
+							"address":       "Jl. Sudirman No. 1, Jakarta",
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/customers/"+id_customer, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "UNVERIFIED", m["kyc_status"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"status": "PENDING"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/kyc", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "PENDING", m["kyc_status"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"status": "VERIFIED"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/kyc", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "VERIFIED", m["kyc_status"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test investment lifecycle",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]string{
+							"description": "Low risk money market fund",
+							"risk_level":  "LOW",
+							"category":    "MONEY_MARKET",
+							"manager":     "NOBI Asset Management",
+							"status":      "SUSPENDED",
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/investments/"+id_investment, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "SUSPENDED", m["status"])
+						require.Equal(t, "IDR", m["currency"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        1000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, r.StatusCode)
+						require.Equal(t, "INVESTMENT_NOT_OPEN", m["code"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"status": "OPEN"})
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/investments/"+id_investment, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "OPEN", m["status"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test investment rules",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"rules": map[string]float64{"min_redemption": 10000},
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("PATCH", ApiURL+"/api/investments/"+id_investment, bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        500.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/withdraw", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusUnprocessableEntity, r.StatusCode)
+						require.Equal(t, "BELOW_MIN_REDEMPTION", m["code"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test reversed deposits do not count toward the daily limit",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"name":  "asset_" + uuid.New().String()[:8],
+							"rules": map[string]float64{"daily_subscription_limit": 30000},
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/investments", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						id_limited_investment = m["id"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/customers/"+id_customer+"/wallet/top-up", bytes.NewReader([]byte(`{"amount": 50000}`)))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_limited_investment,
+							"amount":        20000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						id_limited_deposit = m["transaction_id"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						body, err := json.Marshal(map[string]string{"reason": "wrong investment"})
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/"+id_limited_deposit+"/reverse", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_limited_investment,
+							"amount":        20000.00,
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/transactions/deposit", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						id_limited_deposit = m["transaction_id"].(string)
+					},
+				},
+			},
+		},
+		{
+			Name: "Test recurring plan lifecycle",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						req := map[string]interface{}{
+							"customer_id":   id_customer,
+							"investment_id": id_investment,
+							"amount":        50000.00,
+							"frequency":     "MONTHLY",
+							"start_date":    "2099-01-31",
+						}
+
+						body, err := json.Marshal(req)
+						require.NoError(t, err)
+
+						return http.NewRequest("POST", ApiURL+"/api/recurring-plans", bytes.NewReader(body))
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusCreated, r.StatusCode)
+						require.Equal(t, "ACTIVE", m["status"])
+						require.Equal(t, "2099-01-31", m["next_run_date"])
+						id_plan = m["id"].(string)
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/recurring-plans/"+id_plan+"/skip", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "2099-02-28", m["next_run_date"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/recurring-plans/"+id_plan+"/pause", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "PAUSED", m["status"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("POST", ApiURL+"/api/recurring-plans/"+id_plan+"/resume", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "ACTIVE", m["status"])
+					},
+				},
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("DELETE", ApiURL+"/api/recurring-plans/"+id_plan, nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, "CANCELLED", m["status"])
+					},
+				},
+			},
+		},
+		{
+			Name: "Test audit log hash chain is intact",
+			Steps: []TestCaseStep{
+				{
+					Request: func(t *testing.T, ctx context.Context, tc *TestCase) (*http.Request, error) {
+						return http.NewRequest("GET", ApiURL+"/api/audit/verify", nil)
+					},
+					Expect: func(t *testing.T, ctx context.Context, tc *TestCase, r *http.Response, m map[string]any) {
+						require.Equal(t, http.StatusOK, r.StatusCode)
+						require.Equal(t, true, m["valid"])
+						require.Greater(t, m["entries"].(float64), float64(0))
+					},
+				},
+			},
+		},
+	}
+}
+
+type TestCase struct {
+	Name  string
+	Steps []TestCaseStep
+}
+
+type RequestFunc func(*testing.T, context.Context, *TestCase) (*http.Request, error)
+type ExpectFunc func(*testing.T, context.Context, *TestCase, *http.Response, map[string]any)
+
+type TestCaseStep struct {
+	Request RequestFunc
+	Expect  ExpectFunc
+	Result  map[string]any
+}
+
+// SignToken returns a token of a staff member, or of the customer when
+// customerID is set
+func SignToken(t *testing.T, customerID string) string {
+	claims := jwt.MapClaims{
+		"sub":   "integration-tests",
+		"roles": []string{"operator", "fund-admin", "auditor"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	if customerID != "" {
+		claims["sub"] = customerID
+		claims["customer_id"] = customerID
+		claims["roles"] = []string{"customer"}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(JWTSecret))
+	require.NoError(t, err)
+	return token
+}
+
+// SignRequest authenticates a request with an API key and signs it
+func SignRequest(req *http.Request, key, secret string, body []byte) {
+	timestamp, nonce := strconv.FormatInt(time.Now().Unix(), 10), uuid.NewString()
+	req.Header.Set("X-API-Key", key)
+	req.Header.Set(signature.HeaderTimestamp, timestamp)
+	req.Header.Set(signature.HeaderNonce, nonce)
+	req.Header.Set(signature.HeaderSignature, signature.Sign(secret, req.Method, req.URL.RequestURI(), timestamp, nonce, body))
 }
 
 func ResponseContains(t *testing.T, resp *http.Response, text string) {